@@ -4,8 +4,8 @@ import (
 	"github.com/tsunyoku/danser/app/bmath"
 	"github.com/tsunyoku/danser/app/settings"
 	"github.com/tsunyoku/danser/app/skin"
+	"github.com/tsunyoku/danser/app/utils"
 	"github.com/tsunyoku/danser/framework/bass"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -37,6 +37,13 @@ func AddListener(function func(sampleSet int, hitsoundIndex, index int, volume f
 	listeners = append(listeners, function)
 }
 
+var hitSoundListeners = make([]func(sampleSet, additionSet, hitsound, index int), 0)
+
+// AddHitSoundListener registers a function called with the whole hitsound on every PlaySample
+func AddHitSoundListener(function func(sampleSet, additionSet, hitsound, index int)) {
+	hitSoundListeners = append(hitSoundListeners, function)
+}
+
 func LoadSamples() {
 	Samples[0][0] = LoadSample("normal-hitnormal")
 	Samples[0][1] = LoadSample("normal-hitwhistle")
@@ -61,6 +68,17 @@ func LoadSamples() {
 	Samples[2][4] = LoadSample("drum-slidertick")
 	Samples[2][5] = LoadSample("drum-sliderslide")
 	Samples[2][6] = LoadSample("drum-sliderwhistle")
+
+	for i := range Samples {
+		setBus(Samples[i][5], bass.BusSliderLoops)
+		setBus(Samples[i][6], bass.BusSliderLoops)
+	}
+}
+
+func setBus(sample *bass.Sample, bus bass.Bus) {
+	if sample != nil {
+		sample.SetBus(bus)
+	}
 }
 
 func PlaySample(sampleSet, additionSet, hitsound, index int, volume float64, objNum int64, xPos float64) {
@@ -68,6 +86,10 @@ func PlaySample(sampleSet, additionSet, hitsound, index int, volume float64, obj
 		additionSet = sampleSet
 	}
 
+	for _, f := range hitSoundListeners {
+		f(bmath.ClampI(sampleSet, 1, 3), bmath.ClampI(additionSet, 1, 3), hitsound, index)
+	}
+
 	// Play normal
 	if skin.GetInfo().LayeredHitSounds || hitsound&1 > 0 || hitsound == 0 {
 		playSample(sampleSet, 0, index, volume, objNum, xPos)
@@ -193,7 +215,8 @@ func PlaySliderTick(sampleSet, index int, volume float64, objNum int64, xPos flo
 	playSample(sampleSet, 4, index, volume, objNum, xPos)
 }
 
-func LoadBeatmapSamples(dir string) {
+// LoadBeatmapSamples loads custom hitsounds from beatmap set files
+func LoadBeatmapSamples(files *utils.FileMap) {
 	splitBeforeDigit := func(name string) []string {
 		for i, r := range name {
 			if unicode.IsDigit(r) {
@@ -203,21 +226,21 @@ func LoadBeatmapSamples(dir string) {
 		return []string{name}
 	}
 
-	fullPath := settings.General.OsuSongsDir + string(os.PathSeparator) + dir
+	for _, path := range files.GetFiles() {
+		name := filepath.Base(path)
 
-	filepath.Walk(fullPath, func(path string, info os.FileInfo, err error) error {
-		if !strings.HasSuffix(info.Name(), ".wav") && !strings.HasSuffix(info.Name(), ".mp3") && !strings.HasSuffix(info.Name(), ".ogg") {
-			return nil
+		if !strings.HasSuffix(name, ".wav") && !strings.HasSuffix(name, ".mp3") && !strings.HasSuffix(name, ".ogg") {
+			continue
 		}
 
-		rawName := strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(info.Name(), ".wav"), ".ogg"), ".mp3")
+		rawName := strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(name, ".wav"), ".ogg"), ".mp3")
 
 		if separated := strings.Split(rawName, "-"); len(separated) == 2 {
 
 			setID := sets[separated[0]]
 
 			if setID == 0 {
-				return nil
+				continue
 			}
 
 			subSeparated := splitBeforeDigit(separated[1])
@@ -228,7 +251,7 @@ func LoadBeatmapSamples(dir string) {
 				index, err := strconv.ParseInt(subSeparated[1], 10, 32)
 
 				if err != nil {
-					return nil
+					continue
 				}
 
 				hitSoundIndex = int(index)
@@ -237,21 +260,38 @@ func LoadBeatmapSamples(dir string) {
 			hitSoundID := hitsounds[subSeparated[0]]
 
 			if hitSoundID == 0 {
-				return nil
+				continue
 			}
 
 			if MapSamples[setID-1][hitSoundID-1] == nil {
 				MapSamples[setID-1][hitSoundID-1] = make(map[int]*bass.Sample)
 			}
 
-			MapSamples[setID-1][hitSoundID-1][hitSoundIndex] = bass.NewSample(path)
+			data, err := files.ReadFile(path)
+			if err != nil {
+				continue
+			}
 
-		}
+			sample := bass.NewSampleData(data)
+
+			if hitSoundID == 6 || hitSoundID == 7 {
+				setBus(sample, bass.BusSliderLoops)
+			}
+
+			MapSamples[setID-1][hitSoundID-1][hitSoundIndex] = sample
 
-		return nil
-	})
+		}
+	}
 }
 
 func LoadSample(name string) *bass.Sample {
 	return skin.GetSample(name)
 }
+
+// LoadUISample loads a skin sample that is played on UI bus
+func LoadUISample(name string) *bass.Sample {
+	sample := skin.GetSample(name)
+	setBus(sample, bass.BusUI)
+
+	return sample
+}
@@ -14,12 +14,20 @@ type AudioSprite struct {
 
 	played bool
 	playAt float64
+	volume float64
+
+	condition func(time float64) bool
 }
 
 func NewAudioSprite(sample *bass.Sample, playAt float64) *AudioSprite {
+	return NewAudioSpriteV(sample, playAt, 1)
+}
+
+func NewAudioSpriteV(sample *bass.Sample, playAt, volume float64) *AudioSprite {
 	aSprite := &AudioSprite{
-		Sprite: sprite.NewSpriteSingle(nil, 0, vector.NewVec2d(0,0), vector.NewVec2d(0,0)),
+		Sprite: sprite.NewSpriteSingle(nil, 0, vector.NewVec2d(0, 0), vector.NewVec2d(0, 0)),
 		playAt: playAt,
+		volume: volume,
 		sample: sample,
 	}
 	aSprite.Sprite.ShowForever(true)
@@ -27,10 +35,15 @@ func NewAudioSprite(sample *bass.Sample, playAt float64) *AudioSprite {
 	return aSprite
 }
 
+// SetCondition sets a check done at play time, sample is consumed silently if it returns false
+func (sprite *AudioSprite) SetCondition(condition func(time float64) bool) {
+	sprite.condition = condition
+}
+
 func (sprite *AudioSprite) Update(time float64) {
 	if time >= sprite.playAt && !sprite.played {
-		if sprite.sample != nil {
-			sprite.sample.Play()
+		if sprite.sample != nil && (sprite.condition == nil || sprite.condition(time)) {
+			sprite.sample.PlayRV(sprite.volume)
 		}
 
 		sprite.played = true
@@ -38,7 +51,6 @@ func (sprite *AudioSprite) Update(time float64) {
 	}
 }
 
-
 func (sprite *AudioSprite) Draw(_ float64, _ *batch.QuadBatch) {
 
-}
\ No newline at end of file
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/tsunyoku/danser/app/audio"
 	"github.com/tsunyoku/danser/app/beatmap/difficulty"
 	"github.com/tsunyoku/danser/app/beatmap/objects"
+	"github.com/tsunyoku/danser/app/settings"
 	"math"
 	"strconv"
 	"strings"
@@ -25,6 +26,9 @@ type BeatMap struct {
 	SliderMultiplier float64
 	StackLeniency    float64
 
+	Countdown       int64
+	CountdownOffset int64
+
 	Diff *difficulty.Difficulty
 
 	Dir   string
@@ -61,6 +65,7 @@ func NewBeatMap() *BeatMap {
 	beatMap := &BeatMap{
 		Timings:       objects.NewTimings(),
 		StackLeniency: 0.7,
+		Countdown:     1,
 		Diff:          difficulty.NewDifficulty(5, 5, 5, 5),
 		Stars:         -1,
 		MinBPM:        math.Inf(0),
@@ -101,6 +106,24 @@ func (b *BeatMap) Update(time float64) {
 	}
 }
 
+// GetArtist returns artist in original language if it's preferred and available
+func (beatMap *BeatMap) GetArtist() string {
+	if settings.General.UnicodeMetadata && beatMap.ArtistUnicode != "" {
+		return beatMap.ArtistUnicode
+	}
+
+	return beatMap.Artist
+}
+
+// GetName returns title in original language if it's preferred and available
+func (beatMap *BeatMap) GetName() string {
+	if settings.General.UnicodeMetadata && beatMap.NameUnicode != "" {
+		return beatMap.NameUnicode
+	}
+
+	return beatMap.Name
+}
+
 func (beatMap *BeatMap) GetObjectsCopy() []objects.IHitObject {
 	queue := make([]objects.IHitObject, len(beatMap.HitObjects))
 	copy(queue, beatMap.HitObjects)
@@ -148,7 +171,7 @@ func (beatMap *BeatMap) ParsePoint(point string) {
 }
 
 func (beatMap *BeatMap) LoadCustomSamples() {
-	audio.LoadBeatmapSamples(beatMap.Dir)
+	audio.LoadBeatmapSamples(beatMap.GetFileMap())
 }
 
 func (beatMap *BeatMap) UpdatePlayStats() {
@@ -0,0 +1,107 @@
+package beatmap
+
+import (
+	"errors"
+	"github.com/tsunyoku/danser/app/settings"
+	"github.com/tsunyoku/danser/app/utils"
+	"github.com/tsunyoku/danser/framework/bass"
+	"github.com/tsunyoku/danser/framework/graphics/texture"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var archiveMaps = make(map[string]*utils.FileMap)
+var archiveMutex = &sync.Mutex{}
+
+// IsArchive reports whether set directory is actually an .osz file read in place
+func IsArchive(dir string) bool {
+	return strings.HasSuffix(strings.ToLower(dir), ".osz")
+}
+
+// OpenFile opens a file of the beatmap set located in dir, which may be a directory or an .osz archive
+func OpenFile(dir, name string) (io.ReadCloser, error) {
+	path := filepath.Join(settings.General.OsuSongsDir, dir)
+
+	if IsArchive(dir) {
+		return getArchiveMap(path).Open(name)
+	}
+
+	return os.Open(filepath.Join(path, name))
+}
+
+func (beatMap *BeatMap) IsArchived() bool {
+	return IsArchive(beatMap.Dir)
+}
+
+// getArchiveMap returns index of the archive, archives stay open so their files can be read without indexing them again
+func getArchiveMap(path string) *utils.FileMap {
+	archiveMutex.Lock()
+	defer archiveMutex.Unlock()
+
+	fileMap, ok := archiveMaps[path]
+	if !ok {
+		fileMap = utils.NewFileMap(path)
+		archiveMaps[path] = fileMap
+	}
+
+	return fileMap
+}
+
+// GetFileMap indexes all files in beatmap's set
+func (beatMap *BeatMap) GetFileMap() *utils.FileMap {
+	path := filepath.Join(settings.General.OsuSongsDir, beatMap.Dir)
+
+	if beatMap.IsArchived() {
+		return getArchiveMap(path)
+	}
+
+	return utils.NewFileMap(path)
+}
+
+func (beatMap *BeatMap) OpenFile(name string) (io.ReadCloser, error) {
+	return OpenFile(beatMap.Dir, name)
+}
+
+func (beatMap *BeatMap) ReadFile(name string) ([]byte, error) {
+	file, err := beatMap.OpenFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return ioutil.ReadAll(file)
+}
+
+// GetPixmap loads an image from beatmap's set, like the background
+func (beatMap *BeatMap) GetPixmap(name string) (*texture.Pixmap, error) {
+	data, err := beatMap.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("empty file")
+	}
+
+	return texture.NewPixmapFromBytes(data)
+}
+
+// LoadTrack loads beatmap's music, archived sets are decoded from memory
+func (beatMap *BeatMap) LoadTrack() *bass.Track {
+	if !beatMap.IsArchived() {
+		return bass.NewTrack(filepath.Join(settings.General.OsuSongsDir, beatMap.Dir, beatMap.Audio))
+	}
+
+	data, err := beatMap.ReadFile(beatMap.Audio)
+	if err != nil {
+		log.Println("Failed to read beatmap audio:", err)
+	}
+
+	return bass.NewTrackData(data)
+}
@@ -27,20 +27,21 @@ type Circle struct {
 
 	textFade *animation.Glider
 
-	hitCircleTexture *texture.TextureRegion
-	fullTexture      *texture.TextureRegion
-	hitCircle        *sprite.Sprite
-	hitCircleOverlay *sprite.Sprite
-	approachCircle   *sprite.Sprite
-	reverseArrow     *sprite.Sprite
-	sprites          []*sprite.Sprite
-	diff             *difficulty.Difficulty
-	lastTime         float64
-	silent           bool
-	firstEndCircle   bool
-	textureName      string
-	appearTime       float64
-	ArrowRotation    float64
+	hitCircleTexture   *texture.TextureRegion
+	fullTexture        *texture.TextureRegion
+	hitCircle          *sprite.Sprite
+	hitCircleOverlay   *sprite.Sprite
+	approachCircle     *sprite.Sprite
+	reverseArrow       *sprite.Sprite
+	sprites            []*sprite.Sprite
+	diff               *difficulty.Difficulty
+	lastTime           float64
+	silent             bool
+	firstEndCircle     bool
+	textureName        string
+	overlayAboveNumber bool
+	appearTime         float64
+	ArrowRotation      float64
 
 	SliderPoint      bool
 	SliderPointStart bool
@@ -166,6 +167,9 @@ func (circle *Circle) SetDifficulty(diff *difficulty.Difficulty) {
 
 	circle.hitCircle = sprite.NewSpriteSingle(circle.hitCircleTexture, 0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
 	circle.hitCircleOverlay = sprite.NewSpriteSingle(skin.GetTextureSource(name+"overlay", skin.GetSource(name)), 0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
+
+	// slider start and end overlays follow the flag of the layer supplying them, not the hit circle one
+	circle.overlayAboveNumber = skin.GetInfoFor(name + "overlay").HitCircleOverlayAboveNumber
 	circle.approachCircle = sprite.NewSpriteSingle(skin.GetTexture("approachcircle"), 0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
 	circle.reverseArrow = sprite.NewSpriteSingle(skin.GetTexture("reversearrow"), 0, vector.NewVec2d(0, 0), bmath.Origin.Centre)
 
@@ -286,7 +290,7 @@ func (circle *Circle) Draw(time float64, color color2.Color, batch *batch.QuadBa
 	circle.hitCircle.Draw(time, batch)
 
 	if settings.DIVIDES < settings.Objects.Colors.MandalaTexturesTrigger {
-		if !skin.GetInfo().HitCircleOverlayAboveNumber {
+		if !circle.overlayAboveNumber {
 			circle.hitCircleOverlay.Draw(time, batch)
 		}
 
@@ -304,7 +308,7 @@ func (circle *Circle) Draw(time float64, color color2.Color, batch *batch.QuadBa
 		batch.SetSubScale(1, 1)
 		batch.SetTranslation(position.Copy64())
 		batch.SetColor(1, 1, 1, alpha)
-		if skin.GetInfo().HitCircleOverlayAboveNumber {
+		if circle.overlayAboveNumber {
 			circle.hitCircleOverlay.Draw(time, batch)
 		}
 	}
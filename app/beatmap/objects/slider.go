@@ -469,6 +469,11 @@ func (slider *Slider) SetDifficulty(diff *difficulty.Difficulty) {
 	slider.body = sliderrenderer.NewBody(slider.multiCurve, diff.Mods&difficulty.HardRock > 0, float32(slider.diff.CircleRadius))
 }
 
+// GetDifficulty returns difficulty the slider was set up with
+func (slider *Slider) GetDifficulty() *difficulty.Difficulty {
+	return slider.diff
+}
+
 func (slider *Slider) IsRetarded() bool {
 	return len(slider.scorePath) == 0 || slider.StartTime == slider.EndTime
 }
@@ -574,7 +579,7 @@ func (slider *Slider) Update(time float64) bool {
 		}
 
 		if slider.ball != nil {
-			slider.ball.SetHFlip(skin.GetInfo().SliderBallFlip && reversed)
+			slider.ball.SetHFlip(skin.GetInfoFor("sliderb0").SliderBallFlip && reversed)
 			slider.ball.SetRotation(float64(angle))
 		}
 	}
@@ -791,7 +796,9 @@ func (slider *Slider) DrawBody(_ float64, bodyColor, innerBorder, outerBorder co
 	bodyOuter := color2.NewL(0)
 
 	if settings.Skin.UseColorsFromSkin {
-		borderOuter = skin.GetInfo().SliderBorder
+		circleInfo := skin.GetInfoFor("hitcircle")
+
+		borderOuter = circleInfo.SliderBorder
 		borderInner = borderOuter
 
 		borderOuter.A = float32(colorAlpha)
@@ -799,8 +806,8 @@ func (slider *Slider) DrawBody(_ float64, bodyColor, innerBorder, outerBorder co
 
 		var baseTrack color2.Color
 
-		if skin.GetInfo().SliderTrackOverride != nil {
-			baseTrack = *skin.GetInfo().SliderTrackOverride
+		if circleInfo.SliderTrackOverride != nil {
+			baseTrack = *circleInfo.SliderTrackOverride
 		} else {
 			baseTrack = skin.GetColor(int(slider.ComboSet), int(slider.ComboSetHax), baseTrack)
 		}
@@ -921,7 +928,7 @@ func (slider *Slider) Draw(time float64, color color2.Color, batch *batch.QuadBa
 func (slider *Slider) drawBall(time float64, batch *batch.QuadBatch, color color2.Color, alpha float64, useBallTexture bool) {
 	batch.SetTranslation(slider.ball.GetPosition())
 
-	isB := skin.GetSource("sliderb") != skin.SKIN && useBallTexture
+	isB := skin.GetSource("sliderb")&skin.SKIN == 0 && useBallTexture
 
 	if isB && skin.GetTexture("sliderb-nd") != nil {
 		batch.SetColor(0.1, 0.1, 0.1, alpha*slider.ball.GetAlpha())
@@ -931,10 +938,12 @@ func (slider *Slider) drawBall(time float64, batch *batch.QuadBatch, color color
 	if settings.Skin.UseColorsFromSkin {
 		color := color2.NewL(1)
 
-		if skin.GetInfo().SliderBallTint {
+		ballInfo := skin.GetInfoFor("sliderb0")
+
+		if ballInfo.SliderBallTint {
 			color = skin.GetColor(int(slider.ComboSet), int(slider.ComboSetHax), color)
-		} else if skin.GetInfo().SliderBall != nil {
-			color = *skin.GetInfo().SliderBall
+		} else if ballInfo.SliderBall != nil {
+			color = *ballInfo.SliderBall
 		}
 
 		batch.SetColor(float64(color.R), float64(color.G), float64(color.B), alpha)
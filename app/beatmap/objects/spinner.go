@@ -102,7 +102,9 @@ func (spinner *Spinner) SetDifficulty(diff *difficulty.Difficulty) {
 	spinner.sprites = sprite.NewSpriteManager()
 	spinner.frontSprites = sprite.NewSpriteManager()
 
-	spinner.newStyle = skin.GetTexture("spinner-background") == nil
+	// 1.0 skins use the old spinner if they provide one, otherwise the style depends on which textures are present
+	oldStyle := skin.GetTexture("spinner-background") != nil || (skin.GetInfoFor("spinner-circle").Version < 2.0 && skin.GetSource("spinner-circle")&skin.SKIN > 0)
+	spinner.newStyle = !oldStyle
 
 	if spinner.newStyle {
 		spinner.glow = sprite.NewSpriteSingle(skin.GetTexture("spinner-glow"), 0.0, spinner.StartPosRaw.Copy64(), bmath.Origin.Centre)
@@ -124,6 +126,7 @@ func (spinner *Spinner) SetDifficulty(diff *difficulty.Difficulty) {
 		spinner.middle.ResetValuesToTransforms()
 	} else {
 		spinner.background = sprite.NewSpriteSingle(skin.GetTexture("spinner-background"), 0.0, vector.NewVec2d(spinner.ScaledWidth/2, 46.5+350.4), bmath.Origin.Centre)
+		spinner.background.SetColor(skin.GetInfoFor("spinner-background").SpinnerBackground)
 		spinner.metre = sprite.NewSpriteSingle(skin.GetTexture("spinner-metre"), 2.0, vector.NewVec2d(spinner.ScaledWidth/2-512, 47.5), bmath.Origin.TopLeft) //nolint:misspell
 		spinner.metre.SetCutOrigin(bmath.Origin.BottomCentre)
 
@@ -253,9 +256,10 @@ func (spinner *Spinner) Draw(time float64, color color2.Color, batch *batch.Quad
 	}
 
 	scoreFont := skin.GetFont("score")
+	textColor := skin.GetInfoFor("spinner-rpm").SpinnerTextColour
 
 	if spinner.bonusFade.GetValue() > 0.01 {
-		batch.SetColor(1.0, 1.0, 1.0, spinner.bonusFade.GetValue()*alpha)
+		batch.SetColor32(textColor.R, textColor.G, textColor.B, float32(spinner.bonusFade.GetValue()*alpha))
 
 		scoreFont.DrawOrigin(batch, 256, 192+80, bmath.Origin.Centre, spinner.bonusScale.GetValue()*scoreFont.GetSize()*0.8, false, strconv.Itoa(spinner.bonus))
 	}
@@ -273,9 +277,13 @@ func (spinner *Spinner) Draw(time float64, color color2.Color, batch *batch.Quad
 
 	spinner.rpmBg.Draw(time, batch)
 
+	batch.SetColor32(textColor.R, textColor.G, textColor.B, float32(alpha))
+
 	rpmTxt := fmt.Sprintf("%d", int(spinner.rpm))
 	scoreFont.DrawOrigin(batch, spinner.ScaledWidth/2+139, spinner.ScaledHeight-56, bmath.Origin.TopRight, scoreFont.GetSize(), false, rpmTxt)
 
+	batch.SetColor(1.0, 1.0, 1.0, alpha)
+
 	batch.SetCamera(oldCamera)
 	batch.ResetTransform()
 	batch.SetScale(scale.X, scale.Y)
@@ -76,6 +76,23 @@ func (tim *Timings) GetPoint(time float64) TimingPoint {
 	return tim.Points[len(tim.Points)-1]
 }
 
+// GetOriginalPoint returns the last uninherited timing point at given time
+func (tim *Timings) GetOriginalPoint(time float64) TimingPoint {
+	point := tim.Points[0]
+
+	for _, pt := range tim.Points {
+		if pt.Time > time {
+			break
+		}
+
+		if pt.beatLen > 0 {
+			point = pt
+		}
+	}
+
+	return point
+}
+
 func (tim *Timings) GetSliderTime(pixelLength float64) int64 {
 	return int64(tim.partBPM * pixelLength / (100.0 * tim.SliderMult))
 }
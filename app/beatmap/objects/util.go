@@ -12,7 +12,7 @@ func CreateObject(data []string) IHitObject {
 	if (objType & CIRCLE) > 0 {
 		return NewCircle(data)
 	} else if (objType & SPINNER) > 0 {
-		if settings.Objects.LoadSpinners || settings.KNOCKOUT || settings.PLAY {
+		if settings.Objects.LoadSpinners || settings.KNOCKOUT || settings.PLAY || (settings.Dance.Battle && settings.TAG > 1) {
 			return NewSpinner(data)
 		}
 	} else if (objType & SLIDER) > 0 {
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"github.com/tsunyoku/danser/app/beatmap/objects"
 	"github.com/tsunyoku/danser/app/bmath"
-	"github.com/tsunyoku/danser/app/settings"
 	"github.com/tsunyoku/danser/app/skin"
 	"github.com/tsunyoku/danser/framework/util"
 	"math"
@@ -30,6 +29,10 @@ func parseGeneral(line []string, beatMap *BeatMap) bool {
 		beatMap.Audio += line[1]
 	case "PreviewTime":
 		beatMap.PreviewTime, _ = strconv.ParseInt(line[1], 10, 64)
+	case "Countdown":
+		beatMap.Countdown, _ = strconv.ParseInt(line[1], 10, 64)
+	case "CountdownOffset":
+		beatMap.CountdownOffset, _ = strconv.ParseInt(line[1], 10, 64)
 	case "SampleSet":
 		switch line[1] {
 		case "Normal", "All":
@@ -142,7 +145,7 @@ func getSection(line string) string {
 }
 
 func ParseBeatMap(beatMap *BeatMap) error {
-	file, err := os.Open(filepath.Join(settings.General.OsuSongsDir, beatMap.Dir, beatMap.File))
+	file, err := beatMap.OpenFile(beatMap.File)
 	if err != nil {
 		return err
 	}
@@ -214,8 +217,6 @@ func ParseBeatMap(beatMap *BeatMap) error {
 		}
 	}
 
-	file.Seek(0, 0)
-
 	if beatMap.Name+beatMap.Artist+beatMap.Creator == "" || counter == 0 {
 		return errors.New("corrupted file")
 	}
@@ -224,10 +225,16 @@ func ParseBeatMap(beatMap *BeatMap) error {
 }
 
 func ParseBeatMapFile(file *os.File) *BeatMap {
-	beatMap := NewBeatMap()
-	beatMap.Dir = filepath.Base(filepath.Dir(file.Name()))
 	f, _ := file.Stat()
-	beatMap.File = f.Name()
+
+	return LoadBeatMap(filepath.Base(filepath.Dir(file.Name())), f.Name())
+}
+
+// LoadBeatMap parses the .osu file named file in set dir, dir may be an .osz archive
+func LoadBeatMap(dir, file string) *BeatMap {
+	beatMap := NewBeatMap()
+	beatMap.Dir = dir
+	beatMap.File = file
 
 	err := ParseBeatMap(beatMap)
 
@@ -243,7 +250,7 @@ func ParseTimingPointsAndPauses(beatMap *BeatMap) {
 		return
 	}
 
-	file, err := os.Open(filepath.Join(settings.General.OsuSongsDir, beatMap.Dir, beatMap.File))
+	file, err := beatMap.OpenFile(beatMap.File)
 	if err != nil {
 		panic(err)
 	}
@@ -277,7 +284,7 @@ func ParseTimingPointsAndPauses(beatMap *BeatMap) {
 }
 
 func ParseObjects(beatMap *BeatMap) {
-	file, err := os.Open(filepath.Join(settings.General.OsuSongsDir, beatMap.Dir, beatMap.File))
+	file, err := beatMap.OpenFile(beatMap.File)
 	if err != nil {
 		panic(err)
 	}
@@ -303,6 +310,11 @@ func ParseObjects(beatMap *BeatMap) {
 		}
 
 		switch currentSection {
+		case "General":
+			// Countdown is not stored in the database so it has to be read with objects
+			if arr := tokenizeN(line, ":", 2); len(arr) > 1 && strings.HasPrefix(arr[0], "Countdown") {
+				parseGeneral(arr, beatMap)
+			}
 		case "Colours": //nolint:misspell
 			if arr := tokenize(line, ":"); arr != nil {
 				skin.AddBeatmapColor(arr)
@@ -158,6 +158,20 @@ func (camera *Camera) GenRotated(rotations int, rotOffset float64) []mgl32.Mat4
 	return camera.cache
 }
 
+// GenTransformed works like GenRotated but applies local transformation in world space to each copy before rotating it
+func (camera *Camera) GenTransformed(rotations int, rotOffset float64, local []mgl32.Mat4) []mgl32.Mat4 {
+	pos := mgl32.Translate3D(camera.position.X32(), camera.position.Y32(), 0)
+	view := mgl32.HomogRotate3DZ(float32(camera.rotation)).Mul4(mgl32.Scale3D(camera.scale.X32(), camera.scale.Y32(), 1)).Mul4(mgl32.Translate3D(camera.origin.X32(), camera.origin.Y32(), 0))
+
+	cameras := make([]mgl32.Mat4, rotations)
+
+	for i := 0; i < rotations; i++ {
+		cameras[i] = camera.projection.Mul4(pos).Mul4(mgl32.HomogRotate3DZ(float32(i) * float32(rotOffset))).Mul4(view).Mul4(local[i])
+	}
+
+	return cameras
+}
+
 func (camera Camera) GetProjectionView() mgl32.Mat4 {
 	return camera.projectionView
 }
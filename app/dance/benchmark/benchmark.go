@@ -0,0 +1,273 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/faiface/mainthread"
+	"github.com/tsunyoku/danser/app/beatmap"
+	"github.com/tsunyoku/danser/app/beatmap/difficulty"
+	"github.com/tsunyoku/danser/app/beatmap/objects"
+	"github.com/tsunyoku/danser/app/dance"
+	"github.com/tsunyoku/danser/app/dance/movers"
+	"github.com/tsunyoku/danser/app/rulesets/osu"
+	"github.com/tsunyoku/danser/app/settings"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+)
+
+// Fixed seed used when Dance.Seed isn't set so results are comparable between runs and maps
+const defaultSeed = 1
+
+// Result holds quality metrics of a single mover on a single beatmap, distances are in osu!pixels, times in ms
+type Result struct {
+	Beatmap string `json:"beatmap"`
+	Mover   string `json:"mover"`
+
+	Objects  int     `json:"objects"`
+	OnObject int     `json:"onObject"` // cursor was inside the circle and the object could be hit at its start time
+	Accuracy float64 `json:"accuracy"`
+	Misses   int64   `json:"misses"`
+
+	PathLength  float64 `json:"pathLength"`
+	MeanVel     float64 `json:"meanVelocity"`
+	MaxVel      float64 `json:"maxVelocity"`
+	MeanAccel   float64 `json:"meanAcceleration"`
+	MaxAccel    float64 `json:"maxAcceleration"`
+	MeanJerk    float64 `json:"meanJerk"`
+	OutsideTime float64 `json:"outsideTime"` // time spent outside the playfield
+	BounceTime  float64 `json:"bounceTime"`  // time cursor was reflected by BounceOnEdges
+	Bounces     int     `json:"bounces"`
+}
+
+// Run benchmarks all registered movers on given beatmaps, output file format is picked by extension (.json or text table), empty file prints to stdout
+func Run(beatMaps []*beatmap.BeatMap, mods difficulty.Modifier, output string) {
+	moversBefore := settings.Dance.Movers
+	sectionsBefore := settings.Dance.MoverSections
+	exportBefore := settings.Dance.CursorPath.Export
+	seedBefore := settings.Dance.Seed
+	tagBefore := settings.TAG
+	ripplesBefore := settings.Cursor.CursorRipples
+
+	defer func() {
+		settings.Dance.Movers = moversBefore
+		settings.Dance.MoverSections = sectionsBefore
+		settings.Dance.CursorPath.Export = exportBefore
+		settings.Dance.Seed = seedBefore
+		settings.TAG = tagBefore
+		settings.Cursor.CursorRipples = ripplesBefore
+	}()
+
+	settings.Dance.MoverSections = nil
+	settings.Dance.CursorPath.Export = ""
+	settings.TAG = 1
+
+	// Ripples would load textures off the main thread
+	settings.Cursor.CursorRipples = false
+
+	if settings.Dance.Seed <= 0 {
+		settings.Dance.Seed = defaultSeed
+	}
+
+	results := make([]*Result, 0)
+
+	for _, beatMap := range beatMaps {
+		name := fmt.Sprintf("%s - %s [%s]", beatMap.Artist, beatMap.Name, beatMap.Difficulty)
+
+		log.Println("Benchmarking:", name)
+
+		mainthread.Call(func() {
+			beatMap.Diff.SetMods(mods)
+			beatmap.ParseTimingPointsAndPauses(beatMap)
+			beatmap.ParseObjects(beatMap)
+		})
+
+		if len(beatMap.HitObjects) == 0 {
+			log.Println("Beatmap has no objects, skipping...")
+			continue
+		}
+
+		for _, mover := range movers.GetMoverNames() {
+			settings.Dance.Movers = []string{mover}
+
+			result := runMover(beatMap)
+			result.Beatmap = name
+			result.Mover = mover
+
+			results = append(results, result)
+		}
+
+		// Objects are parsed only for benchmark, free them before going to next map
+		beatMap.HitObjects = nil
+	}
+
+	if err := write(results, output); err != nil {
+		log.Println("Failed to write benchmark results:", err)
+	}
+}
+
+func runMover(beatMap *beatmap.BeatMap) *Result {
+	controller := dance.NewGenericController()
+	controller.SetBeatMap(beatMap)
+
+	// Cursors create their framebuffers on creation
+	mainthread.Call(controller.InitCursors)
+
+	defer controller.Dispose()
+
+	cursor := controller.GetCursors()[0]
+	cursor.IsPlayer = true
+	cursor.IsAutoplay = true
+
+	ruleset := osu.NewOsuRuleset(beatMap, controller.GetCursors(), []difficulty.Modifier{beatMap.Diff.Mods})
+	player := ruleset.GetPlayer(cursor)
+
+	result := &Result{Objects: len(beatMap.HitObjects)}
+
+	startTime := int64(beatMap.HitObjects[0].GetStartTime())
+	endTime := int64(beatMap.HitObjects[len(beatMap.HitObjects)-1].GetEndTime())
+
+	objectIndex := 0
+
+	var lastPos, lastVel, lastAccel vector.Vector2f
+	var samples, accelSamples, jerkSamples int
+
+	bounced := false
+
+	for time := startTime - 1000; time <= endTime; time++ {
+		controller.Update(float64(time), 1)
+
+		pos := cursor.RawPosition
+
+		// Hit check has to be done before ruleset processes clicks at this time
+		for ; objectIndex < len(beatMap.HitObjects) && int64(beatMap.HitObjects[objectIndex].GetStartTime()) <= time; objectIndex++ {
+			obj := beatMap.HitObjects[objectIndex]
+
+			if _, ok := obj.(*objects.Spinner); ok {
+				result.OnObject++
+				continue
+			}
+
+			objPos := obj.GetStackedPositionAtMod(obj.GetStartTime(), beatMap.Diff.Mods)
+
+			if float64(pos.Dst(objPos)) > beatMap.Diff.CircleRadius {
+				continue
+			}
+
+			for _, o := range ruleset.GetProcessed() {
+				if o.GetNumber() == obj.GetID() && ruleset.CanBeHit(time, o, player) == osu.Click {
+					result.OnObject++
+					break
+				}
+			}
+		}
+
+		ruleset.UpdateClickFor(cursor, time)
+		ruleset.UpdateNormalFor(cursor, time)
+		ruleset.UpdatePostFor(cursor, time)
+		ruleset.Update(time)
+
+		if pos.X < 0 || pos.X > 512 || pos.Y < 0 || pos.Y > 384 {
+			result.OutsideTime++
+		}
+
+		if cursor.Position != pos && !cursor.InvertDisplay {
+			result.BounceTime++
+
+			if !bounced {
+				result.Bounces++
+			}
+
+			bounced = true
+		} else {
+			bounced = false
+		}
+
+		if time > startTime-1000 {
+			vel := pos.Sub(lastPos)
+			speed := float64(vel.Len())
+
+			result.PathLength += speed
+			result.MaxVel = math.Max(result.MaxVel, speed)
+			samples++
+
+			if samples > 1 {
+				accel := vel.Sub(lastVel)
+				accelLen := float64(accel.Len())
+
+				result.MeanAccel += accelLen
+				result.MaxAccel = math.Max(result.MaxAccel, accelLen)
+				accelSamples++
+
+				if accelSamples > 1 {
+					result.MeanJerk += float64(accel.Sub(lastAccel).Len())
+					jerkSamples++
+				}
+
+				lastAccel = accel
+			}
+
+			lastVel = vel
+		}
+
+		lastPos = pos
+	}
+
+	if samples > 0 {
+		result.MeanVel = result.PathLength / float64(samples)
+	}
+
+	if accelSamples > 0 {
+		result.MeanAccel /= float64(accelSamples)
+	}
+
+	if jerkSamples > 0 {
+		result.MeanJerk /= float64(jerkSamples)
+	}
+
+	accuracy, _, _, _ := ruleset.GetResults(cursor)
+	_, _, _, misses, _, _ := ruleset.GetHits(cursor)
+
+	result.Accuracy = accuracy
+	result.Misses = misses
+
+	return result
+}
+
+func write(results []*Result, output string) error {
+	var writer io.Writer = os.Stdout
+
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		writer = f
+	}
+
+	if strings.ToLower(filepath.Ext(output)) == ".json" {
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "\t")
+
+		return encoder.Encode(results)
+	}
+
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(table, "Beatmap\tMover\tOn object\tAccuracy\tMisses\tPath\tVel mean/max\tAccel mean/max\tJerk\tOutside\tBounce time/count\t")
+
+	for _, r := range results {
+		fmt.Fprintf(table, "%s\t%s\t%d/%d\t%.2f%%\t%d\t%.0f\t%.2f/%.2f\t%.3f/%.3f\t%.4f\t%.0fms\t%.0fms/%d\t\n",
+			r.Beatmap, r.Mover, r.OnObject, r.Objects, r.Accuracy, r.Misses, r.PathLength, r.MeanVel, r.MaxVel, r.MeanAccel, r.MaxAccel, r.MeanJerk, r.OutsideTime, r.BounceTime, r.Bounces)
+	}
+
+	return table.Flush()
+}
@@ -4,10 +4,14 @@ import (
 	"github.com/tsunyoku/danser/app/beatmap"
 	"github.com/tsunyoku/danser/app/beatmap/objects"
 	"github.com/tsunyoku/danser/app/dance/movers"
+	"github.com/tsunyoku/danser/app/dance/path"
 	"github.com/tsunyoku/danser/app/dance/schedulers"
 	"github.com/tsunyoku/danser/app/dance/spinners"
 	"github.com/tsunyoku/danser/app/graphics"
 	"github.com/tsunyoku/danser/app/settings"
+	"log"
+	"math"
+	"math/rand"
 	"strings"
 )
 
@@ -16,16 +20,28 @@ type Controller interface {
 	InitCursors()
 	Update(time float64, delta float64)
 	GetCursors() []*graphics.Cursor
+	Dispose()
 }
 
 type GenericController struct {
 	bMap       *beatmap.BeatMap
 	cursors    []*graphics.Cursor
 	schedulers []schedulers.Scheduler
+
+	exportPath *path.Path
+	nextSample float64
+	exportEnd  float64
+
+	battleIndex int
 }
 
 func NewGenericController() Controller {
-	return &GenericController{}
+	return &GenericController{battleIndex: -1}
+}
+
+// newBattleController creates a controller with a single cursor playing the whole map as index-th battle cursor
+func newBattleController(index int) *GenericController {
+	return &GenericController{battleIndex: index}
 }
 
 func (controller *GenericController) SetBeatMap(beatMap *beatmap.BeatMap) {
@@ -33,49 +49,43 @@ func (controller *GenericController) SetBeatMap(beatMap *beatmap.BeatMap) {
 }
 
 func (controller *GenericController) InitCursors() {
-	controller.cursors = make([]*graphics.Cursor, settings.TAG)
-	controller.schedulers = make([]schedulers.Scheduler, settings.TAG)
+	numCursors := settings.TAG
+	if controller.battleIndex >= 0 {
+		numCursors = 1
+	}
+
+	controller.cursors = make([]*graphics.Cursor, numCursors)
+	controller.schedulers = make([]schedulers.Scheduler, numCursors)
+
+	seed := settings.Dance.GetSeed(controller.bMap.MD5)
+
+	log.Println("Cursor dance seed:", seed)
 
 	// Mover initialization
 	for i := range controller.cursors {
 		controller.cursors[i] = graphics.NewCursor()
 
+		index := controller.cursorIndex(i)
+
 		mover := "flower"
 		if len(settings.Dance.Movers) > 0 {
-			mover = strings.ToLower(settings.Dance.Movers[i%len(settings.Dance.Movers)])
+			mover = strings.ToLower(settings.Dance.Movers[index%len(settings.Dance.Movers)])
 		}
 
-		var scheduler schedulers.Scheduler
-
-		switch mover {
-		case "spline":
-			scheduler = schedulers.NewGenericScheduler(movers.NewSplineMover)
-		case "bezier":
-			scheduler = schedulers.NewGenericScheduler(movers.NewBezierMover)
-		case "circular":
-			scheduler = schedulers.NewGenericScheduler(movers.NewHalfCircleMover)
-		case "linear":
-			scheduler = schedulers.NewGenericScheduler(movers.NewLinearMover)
-		case "axis":
-			scheduler = schedulers.NewGenericScheduler(movers.NewAxisMover)
-		case "exgon":
-			scheduler = schedulers.NewGenericScheduler(movers.NewExGonMover)
-		case "aggressive":
-			scheduler = schedulers.NewGenericScheduler(movers.NewAggressiveMover)
-		case "momentum":
-			scheduler = schedulers.NewGenericScheduler(movers.NewMomentumMover)
-		default:
-			scheduler = schedulers.NewGenericScheduler(movers.NewAngleOffsetMover)
+		if profile := settings.Dance.GetBattleCursor(index); profile != nil && profile.Mover != "" {
+			mover = strings.ToLower(profile.Mover)
 		}
 
-		controller.schedulers[i] = scheduler
+		rng := rand.New(rand.NewSource(seed + int64(index)))
+
+		controller.schedulers[i] = schedulers.NewGenericScheduler(controller.getMoverCtor(index, mover), rng)
 	}
 
 	type Queue struct {
 		objs []objects.IHitObject
 	}
 
-	objs := make([]Queue, settings.TAG)
+	objs := make([]Queue, numCursors)
 
 	queue := controller.bMap.GetObjectsCopy()
 
@@ -87,7 +97,7 @@ func (controller *GenericController) InitCursors() {
 	}
 
 	// Convert sliders to pseudo-circles for tag cursors
-	if !settings.Dance.Battle && settings.Dance.TAGSliderDance && settings.TAG > 1 {
+	if !settings.Dance.Battle && settings.Dance.TAGSliderDance && numCursors > 1 {
 		for i := 0; i < len(queue); i++ {
 			queue = schedulers.PreprocessQueue(i, queue, true)
 		}
@@ -113,23 +123,135 @@ func (controller *GenericController) InitCursors() {
 				objs[i].objs = append(objs[i].objs, o)
 			}
 		} else {
-			i := j % settings.TAG
+			i := j % numCursors
 			objs[i].objs = append(objs[i].objs, o)
 		}
 	}
 
 	//Initialize spinner movers
 	for i := range controller.cursors {
+		index := controller.cursorIndex(i)
+
 		spinMover := "circle"
 		if len(settings.Dance.Spinners) > 0 {
-			spinMover = settings.Dance.Spinners[i%len(settings.Dance.Spinners)]
+			spinMover = settings.Dance.Spinners[index%len(settings.Dance.Spinners)]
+		}
+
+		if profile := settings.Dance.GetBattleCursor(index); profile != nil && profile.Spinner != "" {
+			spinMover = profile.Spinner
 		}
 
 		controller.schedulers[i].Init(objs[i].objs, controller.bMap.Diff.Mods, controller.cursors[i], spinners.GetMoverCtorByName(spinMover), true)
 	}
+
+	if controller.battleIndex < 0 && settings.Dance.CursorPath.Export != "" && settings.Dance.CursorPath.SampleRate > 0 {
+		controller.exportPath = path.NewPath(settings.Dance.CursorPath.SampleRate, len(controller.cursors))
+		controller.nextSample = math.Inf(1)
+
+		if len(queue) > 0 {
+			controller.nextSample = math.Min(0, queue[0].GetStartTime())
+			controller.exportEnd = queue[len(queue)-1].GetEndTime()
+		}
+	}
+}
+
+// cursorIndex maps local cursor index to global one, battle controllers hold only one cursor
+func (controller *GenericController) cursorIndex(i int) int {
+	if controller.battleIndex >= 0 {
+		return controller.battleIndex
+	}
+
+	return i
+}
+
+// newMover creates a mover by name and sets it up for the map and cursor
+func (controller *GenericController) newMover(cursor int, name string) movers.MultiPointMover {
+	mover := movers.GetMoverCtorByName(name)()
+
+	if c, ok := mover.(movers.CursorMover); ok {
+		c.SetCursor(controller.bMap.Diff, cursor)
+	}
+
+	return mover
+}
+
+// getMoverCtor returns constructor of cursor's mover, wrapping it in a switch mover if there are mover sections defined
+func (controller *GenericController) getMoverCtor(cursor int, mover string) func() movers.MultiPointMover {
+	if len(settings.Dance.MoverSections) == 0 {
+		return func() movers.MultiPointMover {
+			return controller.newMover(cursor, mover)
+		}
+	}
+
+	return func() movers.MultiPointMover {
+		var sections []movers.MoverSection
+
+		for _, s := range settings.Dance.MoverSections {
+			if len(s.Movers) == 0 {
+				continue
+			}
+
+			name := s.Movers[cursor%len(s.Movers)]
+
+			for _, r := range controller.getSectionRanges(s.Kiai, s.Parts, s.StartTime, s.EndTime) {
+				sections = append(sections, movers.MoverSection{
+					Start: r[0],
+					End:   r[1],
+					Mover: controller.newMover(cursor, name),
+				})
+			}
+		}
+
+		return movers.NewSwitchMover(controller.newMover(cursor, mover), sections)
+	}
+}
+
+func (controller *GenericController) getSectionRanges(kiai bool, parts []int, startTime, endTime float64) (ranges [][2]float64) {
+	if endTime > startTime {
+		ranges = append(ranges, [2]float64{startTime, endTime})
+	}
+
+	if kiai {
+		kiaiStart := math.NaN()
+
+		for _, p := range controller.bMap.Timings.Points {
+			if p.Kiai && math.IsNaN(kiaiStart) {
+				kiaiStart = p.Time
+			} else if !p.Kiai && !math.IsNaN(kiaiStart) {
+				ranges = append(ranges, [2]float64{kiaiStart, p.Time})
+				kiaiStart = math.NaN()
+			}
+		}
+
+		if !math.IsNaN(kiaiStart) {
+			ranges = append(ranges, [2]float64{kiaiStart, math.Inf(1)})
+		}
+	}
+
+	for _, part := range parts {
+		start, end := math.Inf(-1), math.Inf(1)
+
+		if part > 1 && part-2 < len(controller.bMap.Pauses) {
+			start = controller.bMap.Pauses[part-2].EndTime
+		}
+
+		if part >= 1 && part-1 < len(controller.bMap.Pauses) {
+			end = controller.bMap.Pauses[part-1].StartTime
+		}
+
+		if part >= 1 && part-2 < len(controller.bMap.Pauses) {
+			ranges = append(ranges, [2]float64{start, end})
+		}
+	}
+
+	return
 }
 
 func (controller *GenericController) Update(time float64, delta float64) {
+	if controller.exportPath != nil {
+		controller.sampleCursors(time)
+	}
+
 	for i := range controller.cursors {
 		controller.schedulers[i].Update(time)
 		controller.cursors[i].Update(delta)
@@ -139,6 +261,44 @@ func (controller *GenericController) Update(time float64, delta float64) {
 	}
 }
 
+// sampleCursors records cursor positions at fixed rate, schedulers are time based so they can be evaluated between frames
+func (controller *GenericController) sampleCursors(time float64) {
+	step := 1000 / controller.exportPath.SampleRate
+
+	for ; controller.nextSample < time && controller.nextSample <= controller.exportEnd; controller.nextSample += step {
+		for i, cursor := range controller.cursors {
+			controller.schedulers[i].Update(controller.nextSample)
+
+			controller.exportPath.Add(i, path.Point{
+				Time:  controller.nextSample,
+				X:     cursor.RawPosition.X,
+				Y:     cursor.RawPosition.Y,
+				Left:  cursor.LeftKey,
+				Right: cursor.RightKey,
+			})
+		}
+	}
+
+	if controller.nextSample > controller.exportEnd {
+		if err := controller.exportPath.Save(settings.Dance.CursorPath.Export); err != nil {
+			log.Println("Failed to export cursor path:", err)
+		} else {
+			log.Println("Cursor path exported to:", settings.Dance.CursorPath.Export)
+		}
+
+		controller.exportPath = nil
+	}
+}
+
+// Dispose releases resources held by movers, like Lua states of script movers
+func (controller *GenericController) Dispose() {
+	for _, s := range controller.schedulers {
+		if s != nil {
+			s.Dispose()
+		}
+	}
+}
+
 func (controller *GenericController) GetCursors() []*graphics.Cursor {
 	return controller.cursors
 }
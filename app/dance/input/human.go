@@ -0,0 +1,115 @@
+package input
+
+import (
+	"github.com/tsunyoku/danser/app/beatmap/difficulty"
+	"github.com/tsunyoku/danser/app/beatmap/objects"
+	"github.com/tsunyoku/danser/app/bmath"
+	"github.com/tsunyoku/danser/app/graphics"
+	"github.com/tsunyoku/danser/app/settings"
+	"math"
+	"math/rand"
+)
+
+type keyPress struct {
+	left    bool
+	press   float64
+	release float64
+}
+
+// HumanInputProcessor taps with normally distributed timing errors, alternates keys in streams
+// and randomly misses to reach configured accuracy
+type HumanInputProcessor struct {
+	cursor  *graphics.Cursor
+	presses []keyPress
+	index   int
+}
+
+func NewHumanInputProcessor(objs []objects.IHitObject, cursor *graphics.Cursor, diff *difficulty.Difficulty, rng *rand.Rand, unstableRate, accuracy float64) *HumanInputProcessor {
+	processor := new(HumanInputProcessor)
+	processor.cursor = cursor
+
+	sigma := unstableRate / 10
+	missChance := getMissChance(diff, sigma, accuracy/100)
+
+	lastPress := math.Inf(-1)
+	lastRelease := math.Inf(-1)
+	lastLeft := false
+
+	for _, o := range objs {
+		_, isSpinner := o.(*objects.Spinner)
+
+		if !isSpinner && rng.Float64() < missChance {
+			continue
+		}
+
+		press := o.GetStartTime() + rng.NormFloat64()*sigma
+
+		// it's impossible to press before releasing the same key, keep taps ordered
+		press = math.Max(press, lastPress+1)
+
+		release := math.Max(press+settings.Dance.Humanized.HoldTime*(0.8+rng.Float64()*0.4), o.GetEndTime()+rng.Float64()*20)
+
+		left := true
+		if press-lastPress < settings.Dance.Humanized.StreamThreshold || press < lastRelease {
+			left = !lastLeft
+		}
+
+		processor.presses = append(processor.presses, keyPress{
+			left:    left,
+			press:   press,
+			release: release,
+		})
+
+		lastPress, lastRelease, lastLeft = press, release, left
+	}
+
+	return processor
+}
+
+// getMissChance returns probability of skipping an object so gaussian hit errors with given sigma end up at target accuracy
+func getMissChance(diff *difficulty.Difficulty, sigma, target float64) float64 {
+	hitChance := func(window int64) float64 {
+		if sigma <= 0 {
+			return 1
+		}
+
+		return math.Erf(float64(window) / (sigma * math.Sqrt2))
+	}
+
+	p300 := hitChance(diff.Hit300)
+	p100 := hitChance(diff.Hit100) - p300
+	p50 := hitChance(diff.Hit50) - p300 - p100
+
+	accuracy := p300 + p100/3 + p50/6
+	if accuracy <= 0 {
+		return 0
+	}
+
+	return bmath.ClampF64(1-target/accuracy, 0, 1)
+}
+
+func (processor *HumanInputProcessor) Update(time float64) {
+	for processor.index < len(processor.presses) && processor.presses[processor.index].release < time {
+		processor.index++
+	}
+
+	left, right := false, false
+
+	for i := processor.index; i < len(processor.presses); i++ {
+		p := processor.presses[i]
+		if p.press > time {
+			break
+		}
+
+		if p.release >= time {
+			if p.left {
+				left = true
+			} else {
+				right = true
+			}
+		}
+	}
+
+	processor.cursor.LeftKey = left
+	processor.cursor.RightKey = right
+}
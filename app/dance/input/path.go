@@ -0,0 +1,23 @@
+package input
+
+import (
+	"github.com/tsunyoku/danser/app/dance/path"
+	"github.com/tsunyoku/danser/app/graphics"
+)
+
+// PathInputProcessor replays key states from imported cursor trajectory
+type PathInputProcessor struct {
+	cursor *graphics.Cursor
+	points []path.Point
+}
+
+func NewPathInputProcessor(points []path.Point, cursor *graphics.Cursor) *PathInputProcessor {
+	return &PathInputProcessor{
+		cursor: cursor,
+		points: points,
+	}
+}
+
+func (processor *PathInputProcessor) Update(time float64) {
+	_, processor.cursor.LeftKey, processor.cursor.RightKey = path.PositionAt(processor.points, time)
+}
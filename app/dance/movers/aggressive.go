@@ -16,6 +16,10 @@ type AggressiveMover struct {
 	mods               difficulty.Modifier
 }
 
+func init() {
+	Register("aggressive", NewAggressiveMover)
+}
+
 func NewAggressiveMover() MultiPointMover {
 	return &AggressiveMover{lastAngle: 0}
 }
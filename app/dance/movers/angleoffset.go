@@ -20,6 +20,10 @@ type AngleOffsetMover struct {
 	mods               difficulty.Modifier
 }
 
+func init() {
+	Register("flower", NewAngleOffsetMover)
+}
+
 func NewAngleOffsetMover() MultiPointMover {
 	return &AngleOffsetMover{lastAngle: 0, invert: 1}
 }
@@ -15,6 +15,10 @@ type AxisMover struct {
 	mods               difficulty.Modifier
 }
 
+func init() {
+	Register("axis", NewAxisMover)
+}
+
 func NewAxisMover() MultiPointMover {
 	return &AxisMover{}
 }
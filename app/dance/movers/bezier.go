@@ -19,6 +19,10 @@ type BezierMover struct {
 	mods               difficulty.Modifier
 }
 
+func init() {
+	Register("bezier", NewBezierMover)
+}
+
 func NewBezierMover() MultiPointMover {
 	bm := &BezierMover{invert: 1}
 	bm.pt = vector.NewVec2f(512/2, 384/2)
@@ -10,8 +10,7 @@ import (
 )
 
 type ExGonMover struct {
-	wasFirst bool
-	rand     *rand.Rand
+	rand *rand.Rand
 
 	lastPos  vector.Vector2f
 	nextTime float64
@@ -20,22 +19,23 @@ type ExGonMover struct {
 	mods    difficulty.Modifier
 }
 
+func init() {
+	Register("exgon", NewExGonMover)
+}
+
 func NewExGonMover() MultiPointMover {
-	return &ExGonMover{}
+	return &ExGonMover{rand: rand.New(rand.NewSource(0))}
 }
 
 func (bm *ExGonMover) Reset(mods difficulty.Modifier) {
 	bm.mods = mods
-	bm.wasFirst = false
 }
 
-func (bm *ExGonMover) SetObjects(objs []objects.IHitObject) int {
-	if !bm.wasFirst {
-		bm.rand = rand.New(rand.NewSource((int64(objs[1].GetStartPosition().X)+1000*int64(objs[1].GetStartPosition().Y))*100 + int64(objs[1].GetStartTime())))
-
-		bm.wasFirst = true
-	}
+func (bm *ExGonMover) SetRandom(rng *rand.Rand) {
+	bm.rand = rng
+}
 
+func (bm *ExGonMover) SetObjects(objs []objects.IHitObject) int {
 	prev, next := objs[0], objs[1]
 
 	bm.nextTime = prev.GetEndTime() + float64(settings.Dance.ExGon.Delay)
@@ -0,0 +1,50 @@
+package movers
+
+import (
+	"github.com/tsunyoku/danser/app/beatmap/difficulty"
+	"github.com/tsunyoku/danser/app/beatmap/objects"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func exGonPath(seed int64) []vector.Vector2f {
+	mover := NewExGonMover()
+	mover.(RandomMover).SetRandom(rand.New(rand.NewSource(seed)))
+	mover.Reset(difficulty.None)
+
+	objs := []objects.IHitObject{
+		objects.DummyCircle(vector.NewVec2f(100, 100), 0),
+		objects.DummyCircle(vector.NewVec2f(400, 300), 1000),
+		objects.DummyCircle(vector.NewVec2f(200, 50), 2000),
+	}
+
+	var path []vector.Vector2f
+
+	for i := 0; i < len(objs)-1; i++ {
+		mover.SetObjects(objs[i:])
+
+		for time := objs[i].GetEndTime(); time < mover.GetEndTime(); time += 10 {
+			path = append(path, mover.Update(time))
+		}
+	}
+
+	return path
+}
+
+func TestExGonSeed(t *testing.T) {
+	first := exGonPath(1)
+
+	if len(first) == 0 {
+		t.Fatal("mover produced no positions")
+	}
+
+	if !reflect.DeepEqual(first, exGonPath(1)) {
+		t.Error("same seed produced different paths")
+	}
+
+	if reflect.DeepEqual(first, exGonPath(2)) {
+		t.Error("different seeds produced the same path")
+	}
+}
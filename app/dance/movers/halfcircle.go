@@ -17,6 +17,10 @@ type HalfCircleMover struct {
 	mods               difficulty.Modifier
 }
 
+func init() {
+	Register("circular", NewHalfCircleMover)
+}
+
 func NewHalfCircleMover() MultiPointMover {
 	return &HalfCircleMover{invert: -1}
 }
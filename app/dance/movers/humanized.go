@@ -0,0 +1,141 @@
+package movers
+
+import (
+	"github.com/tsunyoku/danser/app/beatmap/difficulty"
+	"github.com/tsunyoku/danser/app/beatmap/objects"
+	"github.com/tsunyoku/danser/app/bmath"
+	"github.com/tsunyoku/danser/app/dance/input"
+	"github.com/tsunyoku/danser/app/graphics"
+	"github.com/tsunyoku/danser/app/settings"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"math"
+	"math/rand"
+)
+
+// correctionPart is the part of movement time spent on correcting the overshoot
+const correctionPart = 0.25
+
+func init() {
+	Register("humanized", NewHumanizedMover)
+}
+
+// HumanizedMover simulates human aim: Fitts' law movement time, minimum-jerk velocity profile,
+// slightly curved paths and an overshoot followed by a corrective sub-movement. It also taps with human-like timing errors.
+type HumanizedMover struct {
+	diff         *difficulty.Difficulty
+	unstableRate float64
+	accuracy     float64
+	mods         difficulty.Modifier
+	rand         *rand.Rand
+
+	startPos, overshootPos, endPos vector.Vector2f
+	bend                           vector.Vector2f
+
+	departTime, correctTime, arriveTime float64
+	endTime                             float64
+}
+
+func NewHumanizedMover() MultiPointMover {
+	return &HumanizedMover{
+		diff:         difficulty.NewDifficulty(5, 5, 5, 5),
+		unstableRate: settings.Dance.Humanized.UnstableRate,
+		accuracy:     settings.Dance.Humanized.Accuracy,
+		rand:         rand.New(rand.NewSource(0)),
+	}
+}
+
+// SetCursor picks up map difficulty and unstable rate and accuracy of cursor's battle profile
+func (bm *HumanizedMover) SetCursor(diff *difficulty.Difficulty, index int) {
+	bm.diff = diff
+
+	if profile := settings.Dance.GetBattleCursor(index); profile != nil {
+		if profile.UnstableRate > 0 {
+			bm.unstableRate = profile.UnstableRate
+		}
+
+		if profile.Accuracy > 0 {
+			bm.accuracy = profile.Accuracy
+		}
+	}
+}
+
+func (bm *HumanizedMover) GetInputProcessor(objs []objects.IHitObject, cursor *graphics.Cursor) InputProcessor {
+	return input.NewHumanInputProcessor(objs, cursor, bm.diff, bm.rand, bm.unstableRate, bm.accuracy)
+}
+
+func (bm *HumanizedMover) Reset(mods difficulty.Modifier) {
+	bm.mods = mods
+}
+
+func (bm *HumanizedMover) SetRandom(rng *rand.Rand) {
+	bm.rand = rng
+}
+
+func (bm *HumanizedMover) SetObjects(objs []objects.IHitObject) int {
+	end, start := objs[0], objs[1]
+
+	bm.startPos = end.GetStackedEndPositionMod(bm.mods)
+	bm.endPos = start.GetStackedStartPositionMod(bm.mods)
+
+	bm.endTime = start.GetStartTime()
+
+	// Arrive a bit earlier so early taps still land on the circle
+	lead := bmath.ClampF64(bm.unstableRate/10*2, 0, math.Max(0, bm.endTime-end.GetEndTime()))
+	bm.arriveTime = bm.endTime - lead
+
+	dist := float64(bm.startPos.Dst(bm.endPos))
+	width := bm.diff.CircleRadius * 2
+
+	movementTime := settings.Dance.Humanized.FittsA + settings.Dance.Humanized.FittsB*math.Log2(dist/width+1)
+	movementTime *= 0.9 + bm.rand.Float64()*0.2
+
+	bm.departTime = math.Max(end.GetEndTime(), bm.arriveTime-movementTime)
+	bm.correctTime = bm.departTime + (bm.arriveTime-bm.departTime)*(1-correctionPart)
+
+	overshoot := float32(settings.Dance.Humanized.Overshoot * (0.5 + bm.rand.Float64()))
+
+	bm.overshootPos = bm.endPos
+	bm.bend = vector.NewVec2f(0, 0)
+
+	if dist > 0.01 {
+		dir := bm.endPos.Sub(bm.startPos).Nor()
+
+		bm.overshootPos = bm.endPos.Add(dir.Scl(float32(dist) * overshoot))
+
+		side := float32(1)
+		if bm.rand.Intn(2) == 0 {
+			side = -1
+		}
+
+		bm.bend = vector.NewVec2f(-dir.Y, dir.X).Scl(side * float32(dist*settings.Dance.Humanized.Curvature*bm.rand.Float64()))
+	}
+
+	return 2
+}
+
+func (bm *HumanizedMover) Update(time float64) vector.Vector2f {
+	switch {
+	case time <= bm.departTime:
+		return bm.startPos
+	case time < bm.correctTime:
+		t := minJerk((time - bm.departTime) / (bm.correctTime - bm.departTime))
+
+		return bm.startPos.Lerp(bm.overshootPos, float32(t)).Add(bm.bend.Scl(float32(math.Sin(t * math.Pi))))
+	case time < bm.arriveTime:
+		t := minJerk((time - bm.correctTime) / (bm.arriveTime - bm.correctTime))
+
+		return bm.overshootPos.Lerp(bm.endPos, float32(t))
+	}
+
+	return bm.endPos
+}
+
+func (bm *HumanizedMover) GetEndTime() float64 {
+	return bm.endTime
+}
+
+// minJerk is the position profile of minimum-jerk movement
+func minJerk(t float64) float64 {
+	t = bmath.ClampF64(t, 0, 1)
+	return t * t * t * (10 - 15*t + 6*t*t)
+}
@@ -16,6 +16,10 @@ type LinearMover struct {
 	mods               difficulty.Modifier
 }
 
+func init() {
+	Register("linear", NewLinearMover)
+}
+
 func NewLinearMover() MultiPointMover {
 	return &LinearMover{}
 }
@@ -23,6 +23,10 @@ type MomentumMover struct {
 	mods      difficulty.Modifier
 }
 
+func init() {
+	Register("momentum", NewMomentumMover)
+}
+
 func NewMomentumMover() MultiPointMover {
 	return &MomentumMover{last: vector.NewVec2f(0, 0), first: true}
 }
@@ -3,9 +3,41 @@ package movers
 import (
 	"github.com/tsunyoku/danser/app/beatmap/difficulty"
 	"github.com/tsunyoku/danser/app/beatmap/objects"
+	"github.com/tsunyoku/danser/app/graphics"
 	"github.com/tsunyoku/danser/framework/math/vector"
+	"math/rand"
 )
 
+// RandomMover is implemented by movers that use randomness, schedulers pass cursor's RNG to them
+type RandomMover interface {
+	SetRandom(rng *rand.Rand)
+}
+
+// CursorMover is implemented by movers that depend on map difficulty or on the cursor they are assigned to
+type CursorMover interface {
+	SetCursor(diff *difficulty.Difficulty, index int)
+}
+
+// InputProcessor presses cursor's keys
+type InputProcessor interface {
+	Update(time float64)
+}
+
+// InputMover is implemented by movers that also decide when keys are pressed, nil processor means natural input is used
+type InputMover interface {
+	GetInputProcessor(objs []objects.IHitObject, cursor *graphics.Cursor) InputProcessor
+}
+
+// FreeMover is implemented by movers that may control the cursor on their own, objects then neither lock nor snap it
+type FreeMover interface {
+	IgnoresObjects() bool
+}
+
+// DisposableMover is implemented by movers holding resources that have to be released when the dance ends
+type DisposableMover interface {
+	Dispose()
+}
+
 type MultiPointMover interface {
 	Reset(mods difficulty.Modifier)
 	SetObjects(objs []objects.IHitObject) int
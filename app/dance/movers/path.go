@@ -0,0 +1,94 @@
+package movers
+
+import (
+	"github.com/tsunyoku/danser/app/beatmap/difficulty"
+	"github.com/tsunyoku/danser/app/beatmap/objects"
+	"github.com/tsunyoku/danser/app/dance/input"
+	"github.com/tsunyoku/danser/app/dance/path"
+	"github.com/tsunyoku/danser/app/graphics"
+	"github.com/tsunyoku/danser/app/settings"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"log"
+	"math"
+	"sync"
+)
+
+var importMutex = &sync.Mutex{}
+var importedFile string
+var importedPath *path.Path
+
+func init() {
+	Register("path", NewPathMover)
+}
+
+// getImportedPath loads Dance.CursorPath.Import once and shares it between cursors, nil if there's nothing to replay
+func getImportedPath() *path.Path {
+	importMutex.Lock()
+	defer importMutex.Unlock()
+
+	file := settings.Dance.CursorPath.Import
+
+	if file != importedFile {
+		importedFile = file
+		importedPath = nil
+
+		if file != "" {
+			var err error
+			if importedPath, err = path.Load(file); err != nil {
+				log.Println("Failed to load cursor path:", err)
+			}
+		}
+	}
+
+	return importedPath
+}
+
+// PathMover replays imported cursor trajectory, it never ends so it overrides object positions as well
+type PathMover struct {
+	points []path.Point
+}
+
+func NewPathMover() MultiPointMover {
+	return &PathMover{}
+}
+
+// SetCursor picks trajectory of the cursor from imported path
+func (bm *PathMover) SetCursor(_ *difficulty.Difficulty, index int) {
+	if imported := getImportedPath(); imported != nil {
+		bm.points = imported.GetPoints(index)
+	}
+}
+
+// GetInputProcessor replays recorded keys, natural input is used if nothing was imported
+func (bm *PathMover) GetInputProcessor(_ []objects.IHitObject, cursor *graphics.Cursor) InputProcessor {
+	if len(bm.points) == 0 {
+		return nil
+	}
+
+	return input.NewPathInputProcessor(bm.points, cursor)
+}
+
+// IgnoresObjects makes the scheduler follow imported trajectory also while objects are active
+func (bm *PathMover) IgnoresObjects() bool {
+	return len(bm.points) > 0
+}
+
+func (bm *PathMover) Reset(_ difficulty.Modifier) {}
+
+func (bm *PathMover) SetObjects(_ []objects.IHitObject) int {
+	return 2
+}
+
+func (bm *PathMover) Update(time float64) vector.Vector2f {
+	pos, _, _ := path.PositionAt(bm.points, time)
+	return pos
+}
+
+func (bm *PathMover) GetEndTime() float64 {
+	// without imported path objects are followed like with other movers
+	if len(bm.points) == 0 {
+		return math.Inf(-1)
+	}
+
+	return math.Inf(1)
+}
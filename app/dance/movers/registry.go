@@ -0,0 +1,40 @@
+package movers
+
+import (
+	"github.com/tsunyoku/danser/app/dance/script"
+	"sort"
+	"strings"
+)
+
+var registry = make(map[string]func() MultiPointMover)
+
+// Register adds a mover constructor under given name, names are case-insensitive
+func Register(name string, ctor func() MultiPointMover) {
+	registry[strings.ToLower(name)] = ctor
+}
+
+// GetMoverCtorByName returns a registered mover, then script mover from movers directory, flower mover is used as a fallback
+func GetMoverCtorByName(name string) func() MultiPointMover {
+	name = strings.ToLower(name)
+
+	if ctor, ok := registry[name]; ok {
+		return ctor
+	}
+
+	if script.Exists(name) {
+		return NewScriptMoverCtor(name)
+	}
+
+	return NewAngleOffsetMover
+}
+
+func GetMoverNames() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
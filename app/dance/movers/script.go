@@ -0,0 +1,62 @@
+package movers
+
+import (
+	"github.com/tsunyoku/danser/app/beatmap/difficulty"
+	"github.com/tsunyoku/danser/app/beatmap/objects"
+	"github.com/tsunyoku/danser/app/bmath"
+	"github.com/tsunyoku/danser/app/dance/script"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	lua "github.com/yuin/gopher-lua"
+	"log"
+	"math/rand"
+)
+
+// ScriptMover calls reset, set_objects, update and get_end_time functions of a Lua script from movers directory
+type ScriptMover struct {
+	script *script.Script
+	last   vector.Vector2f
+}
+
+// NewScriptMoverCtor returns a constructor of given script mover, it falls back to flower mover if script can't be loaded
+func NewScriptMoverCtor(name string) func() MultiPointMover {
+	return func() MultiPointMover {
+		s, err := script.Load(name)
+		if err != nil {
+			log.Println("Failed to load mover script:", err)
+			return NewAngleOffsetMover()
+		}
+
+		return &ScriptMover{script: s}
+	}
+}
+
+func (bm *ScriptMover) Reset(mods difficulty.Modifier) {
+	bm.script.SetMods(mods)
+	bm.script.Call("reset", 0, lua.LNumber(mods))
+}
+
+func (bm *ScriptMover) Dispose() {
+	bm.script.Close()
+}
+
+func (bm *ScriptMover) SetRandom(rng *rand.Rand) {
+	bm.script.SetRandom(rng)
+}
+
+func (bm *ScriptMover) SetObjects(objs []objects.IHitObject) int {
+	ret := bm.script.Call("set_objects", 1, bm.script.NewObjectList(objs))
+
+	return bmath.ClampI(int(script.Number(ret[0], 2)), 2, len(objs))
+}
+
+func (bm *ScriptMover) Update(time float64) vector.Vector2f {
+	ret := bm.script.Call("update", 2, lua.LNumber(time))
+
+	bm.last = vector.NewVec2f(float32(script.Number(ret[0], float64(bm.last.X))), float32(script.Number(ret[1], float64(bm.last.Y))))
+
+	return bm.last
+}
+
+func (bm *ScriptMover) GetEndTime() float64 {
+	return script.Number(bm.script.Call("get_end_time", 1)[0], 0)
+}
@@ -23,6 +23,10 @@ type SplineMover struct {
 	mods               difficulty.Modifier
 }
 
+func init() {
+	Register("spline", NewSplineMover)
+}
+
 func NewSplineMover() MultiPointMover {
 	return &SplineMover{}
 }
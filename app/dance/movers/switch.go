@@ -0,0 +1,93 @@
+package movers
+
+import (
+	"github.com/tsunyoku/danser/app/beatmap/difficulty"
+	"github.com/tsunyoku/danser/app/beatmap/objects"
+	"github.com/tsunyoku/danser/app/graphics"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"math/rand"
+)
+
+// MoverSection is a time range in which Mover is used
+type MoverSection struct {
+	Start, End float64
+	Mover      MultiPointMover
+}
+
+// SwitchMover picks a mover for each movement by the time it begins, first matching section wins
+type SwitchMover struct {
+	def      MultiPointMover
+	sections []MoverSection
+	current  MultiPointMover
+}
+
+func NewSwitchMover(def MultiPointMover, sections []MoverSection) MultiPointMover {
+	return &SwitchMover{def: def, sections: sections, current: def}
+}
+
+func (bm *SwitchMover) Reset(mods difficulty.Modifier) {
+	bm.def.Reset(mods)
+
+	for _, s := range bm.sections {
+		s.Mover.Reset(mods)
+	}
+
+	bm.current = bm.def
+}
+
+func (bm *SwitchMover) Dispose() {
+	if d, ok := bm.def.(DisposableMover); ok {
+		d.Dispose()
+	}
+
+	for _, s := range bm.sections {
+		if d, ok := s.Mover.(DisposableMover); ok {
+			d.Dispose()
+		}
+	}
+}
+
+func (bm *SwitchMover) SetRandom(rng *rand.Rand) {
+	if r, ok := bm.def.(RandomMover); ok {
+		r.SetRandom(rng)
+	}
+
+	for _, s := range bm.sections {
+		if r, ok := s.Mover.(RandomMover); ok {
+			r.SetRandom(rng)
+		}
+	}
+}
+
+// GetInputProcessor uses keys of the default mover, sections change only cursor movement
+func (bm *SwitchMover) GetInputProcessor(objs []objects.IHitObject, cursor *graphics.Cursor) InputProcessor {
+	if i, ok := bm.def.(InputMover); ok {
+		return i.GetInputProcessor(objs, cursor)
+	}
+
+	return nil
+}
+
+func (bm *SwitchMover) SetObjects(objs []objects.IHitObject) int {
+	bm.current = bm.getMover(objs[0].GetEndTime())
+
+	return bm.current.SetObjects(objs)
+}
+
+func (bm *SwitchMover) getMover(time float64) MultiPointMover {
+	for _, s := range bm.sections {
+		if time >= s.Start && time < s.End {
+			return s.Mover
+		}
+	}
+
+	return bm.def
+}
+
+func (bm *SwitchMover) Update(time float64) vector.Vector2f {
+	return bm.current.Update(time)
+}
+
+func (bm *SwitchMover) GetEndTime() float64 {
+	return bm.current.GetEndTime()
+}
@@ -0,0 +1,231 @@
+package path
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Point is a single sample of cursor trajectory
+type Point struct {
+	Time  float64 `json:"time"`
+	X     float32 `json:"x"`
+	Y     float32 `json:"y"`
+	Left  bool    `json:"left"`
+	Right bool    `json:"right"`
+}
+
+// Path holds sampled trajectories of all cursors
+type Path struct {
+	SampleRate float64   `json:"sampleRate"`
+	Cursors    [][]Point `json:"cursors"`
+}
+
+func NewPath(sampleRate float64, cursors int) *Path {
+	return &Path{
+		SampleRate: sampleRate,
+		Cursors:    make([][]Point, cursors),
+	}
+}
+
+// Load reads a trajectory file, format is picked by extension (.csv or .json)
+func Load(file string) (*Path, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	path := new(Path)
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		if err = json.NewDecoder(f).Decode(path); err != nil {
+			return nil, err
+		}
+	case ".csv":
+		if err = path.readCSV(csv.NewReader(f)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported path format: %s", filepath.Ext(file))
+	}
+
+	for _, points := range path.Cursors {
+		sort.SliceStable(points, func(i, j int) bool {
+			return points[i].Time < points[j].Time
+		})
+	}
+
+	return path, nil
+}
+
+// Save writes the trajectory file, format is picked by extension (.csv or .json)
+func (path *Path) Save(file string) error {
+	if dir := filepath.Dir(file); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "\t")
+
+		return encoder.Encode(path)
+	case ".csv":
+		return path.writeCSV(csv.NewWriter(f))
+	}
+
+	return fmt.Errorf("unsupported path format: %s", filepath.Ext(file))
+}
+
+func (path *Path) writeCSV(writer *csv.Writer) error {
+	if err := writer.Write([]string{"cursor", "time", "x", "y", "left", "right"}); err != nil {
+		return err
+	}
+
+	for i, points := range path.Cursors {
+		for _, p := range points {
+			err := writer.Write([]string{
+				strconv.Itoa(i),
+				strconv.FormatFloat(p.Time, 'f', -1, 64),
+				strconv.FormatFloat(float64(p.X), 'f', -1, 32),
+				strconv.FormatFloat(float64(p.Y), 'f', -1, 32),
+				strconv.FormatBool(p.Left),
+				strconv.FormatBool(p.Right),
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func (path *Path) readCSV(reader *csv.Reader) error {
+	records, err := reader.ReadAll()
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return errors.New("empty path file")
+	}
+
+	// Skip header
+	if _, err := strconv.Atoi(records[0][0]); err != nil {
+		records = records[1:]
+	}
+
+	var lastTime float64
+
+	for i, record := range records {
+		if len(record) < 4 {
+			return fmt.Errorf("line %d: expected at least 4 columns", i+1)
+		}
+
+		cursor, err := strconv.Atoi(record[0])
+		if err != nil || cursor < 0 {
+			return fmt.Errorf("line %d: invalid cursor index", i+1)
+		}
+
+		var values [3]float64
+
+		for j := range values {
+			if values[j], err = strconv.ParseFloat(record[j+1], 64); err != nil {
+				return fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+
+		point := Point{
+			Time: values[0],
+			X:    float32(values[1]),
+			Y:    float32(values[2]),
+		}
+
+		if len(record) > 4 {
+			point.Left, _ = strconv.ParseBool(record[4])
+		}
+
+		if len(record) > 5 {
+			point.Right, _ = strconv.ParseBool(record[5])
+		}
+
+		for len(path.Cursors) <= cursor {
+			path.Cursors = append(path.Cursors, nil)
+		}
+
+		if i > 0 && path.SampleRate == 0 && point.Time > lastTime {
+			path.SampleRate = 1000 / (point.Time - lastTime)
+		}
+
+		lastTime = point.Time
+
+		path.Cursors[cursor] = append(path.Cursors[cursor], point)
+	}
+
+	return nil
+}
+
+// Add appends a sample to cursor's trajectory
+func (path *Path) Add(cursor int, point Point) {
+	path.Cursors[cursor] = append(path.Cursors[cursor], point)
+}
+
+// GetPoints returns trajectory of given cursor, wrapping around if file has less cursors
+func (path *Path) GetPoints(cursor int) []Point {
+	if len(path.Cursors) == 0 {
+		return nil
+	}
+
+	return path.Cursors[cursor%len(path.Cursors)]
+}
+
+// PositionAt linearly interpolates cursor position between samples, keys are taken from previous sample
+func PositionAt(points []Point, time float64) (vector.Vector2f, bool, bool) {
+	if len(points) == 0 {
+		return vector.NewVec2f(0, 0), false, false
+	}
+
+	index := sort.Search(len(points), func(i int) bool {
+		return points[i].Time > time
+	})
+
+	if index == 0 {
+		p := points[0]
+		return vector.NewVec2f(p.X, p.Y), false, false
+	}
+
+	p1 := points[index-1]
+
+	if index == len(points) || points[index].Time == p1.Time {
+		return vector.NewVec2f(p1.X, p1.Y), p1.Left, p1.Right
+	}
+
+	p2 := points[index]
+
+	t := float32((time - p1.Time) / (p2.Time - p1.Time))
+
+	return vector.NewVec2f(p1.X, p1.Y).Lerp(vector.NewVec2f(p2.X, p2.Y), t), p1.Left, p1.Right
+}
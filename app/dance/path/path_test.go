@@ -0,0 +1,87 @@
+package path
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func newTestPath() *Path {
+	path := NewPath(100, 2)
+
+	for i := 0; i < 5; i++ {
+		time := float64(i) * 10
+
+		path.Add(0, Point{Time: time, X: float32(i) * 1.5, Y: 384 - float32(i)*2.25, Left: i%2 == 0})
+		path.Add(1, Point{Time: time, X: 512 - float32(i)*3, Y: float32(i) * 0.5, Right: i > 2})
+	}
+
+	return path
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	for _, ext := range []string{".csv", ".json"} {
+		t.Run(ext, func(t *testing.T) {
+			expected := newTestPath()
+
+			file := filepath.Join(t.TempDir(), "path"+ext)
+
+			if err := expected.Save(file); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+
+			loaded, err := Load(file)
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+
+			if loaded.SampleRate != expected.SampleRate {
+				t.Errorf("sample rate: got %v, want %v", loaded.SampleRate, expected.SampleRate)
+			}
+
+			if !reflect.DeepEqual(loaded.Cursors, expected.Cursors) {
+				t.Errorf("cursors differ:\ngot  %v\nwant %v", loaded.Cursors, expected.Cursors)
+			}
+		})
+	}
+}
+
+func TestLoadUnsupportedFormat(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "path.txt")
+
+	if err := newTestPath().Save(file); err == nil {
+		t.Error("Save accepted unsupported format")
+	}
+
+	if _, err := Load(file); err == nil {
+		t.Error("Load accepted unsupported format")
+	}
+}
+
+func TestPositionAt(t *testing.T) {
+	points := newTestPath().GetPoints(0)
+
+	tests := []struct {
+		time float64
+		x, y float32
+		left bool
+	}{
+		{-10, 0, 384, false},
+		{0, 0, 384, true},
+		{5, 0.75, 382.875, true},
+		{10, 1.5, 381.75, false},
+		{100, 6, 375, true},
+	}
+
+	for _, test := range tests {
+		pos, left, _ := PositionAt(points, test.time)
+
+		if pos.X != test.x || pos.Y != test.y {
+			t.Errorf("time %v: got position %v, want (%v, %v)", test.time, pos, test.x, test.y)
+		}
+
+		if left != test.left {
+			t.Errorf("time %v: got left key %v, want %v", test.time, left, test.left)
+		}
+	}
+}
@@ -66,7 +66,7 @@ func (controller *PlayerController) InitCursors() {
 	controller.window.SetInputMode(glfw.CursorMode, glfw.CursorHidden)
 
 	if controller.bMap.Diff.CheckModActive(difficulty.Relax2) {
-		controller.mouseController = schedulers.NewGenericScheduler(movers.NewLinearMover)
+		controller.mouseController = schedulers.NewGenericScheduler(movers.NewLinearMover, nil)
 		controller.mouseController.Init(controller.bMap.GetObjectsCopy(), controller.bMap.Diff.Mods, controller.cursors[0], spinners.GetMoverCtorByName("circle"), false)
 	} else if settings.Input.MouseHighPrecision {
 		if glfw.RawMouseMotionSupported() {
@@ -177,6 +177,8 @@ func (controller *PlayerController) GetRuleset() *osu.OsuRuleSet {
 	return controller.ruleset
 }
 
+func (controller *PlayerController) Dispose() {}
+
 func (controller *PlayerController) GetCursors() []*graphics.Cursor {
 	return controller.cursors
 }
@@ -1,6 +1,7 @@
 package dance
 
 import (
+	"fmt"
 	"github.com/karrick/godirwalk"
 	"github.com/thehowl/go-osuapi"
 	"github.com/tsunyoku/danser/app/dance/input"
@@ -71,13 +72,24 @@ type ReplayController struct {
 	controllers []*subControl
 	ruleset     *osu.OsuRuleSet
 	lastTime    float64
+	battle      bool
 }
 
 func NewReplayController() Controller {
 	return new(ReplayController)
 }
 
+// NewBattleController creates a controller where each of settings.TAG danser cursors plays the whole map and is scored as a separate player
+func NewBattleController() Controller {
+	return &ReplayController{battle: true}
+}
+
 func (controller *ReplayController) SetBeatMap(beatMap *beatmap.BeatMap) {
+	if controller.battle {
+		controller.setBattleCursors(beatMap)
+		return
+	}
+
 	replayDir := filepath.Join(replaysMaster, beatMap.MD5)
 
 	err := os.MkdirAll(replayDir, 0755)
@@ -237,6 +249,42 @@ func (controller *ReplayController) SetBeatMap(beatMap *beatmap.BeatMap) {
 	controller.lastTime = -200
 }
 
+func (controller *ReplayController) setBattleCursors(beatMap *beatmap.BeatMap) {
+	mods := difficulty.Autoplay | beatMap.Diff.Mods
+
+	for i := 0; i < settings.TAG; i++ {
+		control := NewSubControl()
+
+		control.danceController = newBattleController(i)
+		control.danceController.SetBeatMap(beatMap)
+
+		name := fmt.Sprintf("%s %d", settings.Knockout.DanserName, i+1)
+
+		if len(settings.Dance.Movers) > 0 {
+			name = settings.Dance.Movers[i%len(settings.Dance.Movers)]
+		}
+
+		if profile := settings.Dance.GetBattleCursor(i); profile != nil {
+			if profile.Name != "" {
+				name = profile.Name
+			} else if profile.Mover != "" {
+				name = profile.Mover
+			}
+		}
+
+		// Names have to be unique as overlays identify players by them
+		name += string(rune(unicode.MaxRune - i))
+
+		controller.replays = append(controller.replays, RpData{name, mods.String(), mods, 100, 0, 0, osu.NONE, -1, time.Now()})
+		controller.controllers = append(controller.controllers, control)
+	}
+
+	settings.PLAYERS = len(controller.replays)
+
+	controller.bMap = beatMap
+	controller.lastTime = -200
+}
+
 func loadFrames(subController *subControl, frames []*rplpa.ReplayData) {
 	// Remove mania seed frame if its present
 	for i, frame := range frames {
@@ -303,7 +351,7 @@ func (controller *ReplayController) InitCursors() {
 		}
 
 		if controller.replays[i].ModsV.Active(difficulty.Relax2) {
-			controller.controllers[i].mouseController = schedulers.NewGenericScheduler(movers.NewLinearMover)
+			controller.controllers[i].mouseController = schedulers.NewGenericScheduler(movers.NewLinearMover, nil)
 			controller.controllers[i].mouseController.Init(controller.bMap.GetObjectsCopy(), controller.replays[i].ModsV, controller.cursors[i], spinners.GetMoverCtorByName("circle"), false)
 		}
 	}
@@ -451,6 +499,15 @@ func (controller *ReplayController) updateMain(nTime float64) {
 	controller.lastTime = nTime
 }
 
+// Dispose releases resources of danser cursors playing alongside replays
+func (controller *ReplayController) Dispose() {
+	for _, c := range controller.controllers {
+		if c.danceController != nil {
+			c.danceController.Dispose()
+		}
+	}
+}
+
 func (controller *ReplayController) GetCursors() []*graphics.Cursor {
 	return controller.cursors
 }
@@ -18,12 +18,46 @@ type GenericScheduler struct {
 	queue    []objects.IHitObject
 	mover    movers.MultiPointMover
 	lastTime float64
-	input    *input.NaturalInputProcessor
+	input    inputProcessor
 	mods     difficulty.Modifier
+	rand     *rand.Rand
+
+	spinnerMovers []spinners.SpinnerMover
+
+	inputCtor func(objs []objects.IHitObject, cursor *graphics.Cursor) inputProcessor
+}
+
+type inputProcessor interface {
+	Update(time float64)
 }
 
-func NewGenericScheduler(mover func() movers.MultiPointMover) Scheduler {
-	return &GenericScheduler{mover: mover()}
+// NewGenericScheduler creates a scheduler using cursor's RNG, nil rng means a fixed seed of 0
+func NewGenericScheduler(mover func() movers.MultiPointMover, rng *rand.Rand) Scheduler {
+	if rng == nil {
+		rng = rand.New(rand.NewSource(0))
+	}
+
+	scheduler := &GenericScheduler{mover: mover(), rand: rng}
+
+	scheduler.inputCtor = func(objs []objects.IHitObject, cursor *graphics.Cursor) inputProcessor {
+		return input.NewNaturalInputProcessor(objs, cursor)
+	}
+
+	if r, ok := scheduler.mover.(movers.RandomMover); ok {
+		r.SetRandom(rng)
+	}
+
+	if m, ok := scheduler.mover.(movers.InputMover); ok {
+		scheduler.inputCtor = func(objs []objects.IHitObject, cursor *graphics.Cursor) inputProcessor {
+			if processor := m.GetInputProcessor(objs, cursor); processor != nil {
+				return processor
+			}
+
+			return input.NewNaturalInputProcessor(objs, cursor)
+		}
+	}
+
+	return scheduler
 }
 
 func (scheduler *GenericScheduler) Init(objs []objects.IHitObject, mods difficulty.Modifier, cursor *graphics.Cursor, spinnerMoverCtor func() spinners.SpinnerMover, initKeys bool) {
@@ -32,20 +66,39 @@ func (scheduler *GenericScheduler) Init(objs []objects.IHitObject, mods difficul
 	scheduler.queue = objs
 
 	if initKeys {
-		scheduler.input = input.NewNaturalInputProcessor(objs, cursor)
+		scheduler.input = scheduler.inputCtor(objs, cursor)
 	}
 
 	scheduler.mover.Reset(mods)
 
 	// Slider dance / random slider dance resolving
 	for i := 0; i < len(scheduler.queue); i++ {
-		scheduler.queue = PreprocessQueue(i, scheduler.queue, (settings.Dance.SliderDance && !settings.Dance.RandomSliderDance) || (settings.Dance.RandomSliderDance && rand.Intn(2) == 0))
+		scheduler.queue = PreprocessQueue(i, scheduler.queue, (settings.Dance.SliderDance && !settings.Dance.RandomSliderDance) || (settings.Dance.RandomSliderDance && scheduler.rand.Intn(2) == 0))
+	}
+
+	// Apply slider dance strategies to sliders that weren't converted to circles
+	for i := 0; i < len(scheduler.queue); i++ {
+		if s, ok := scheduler.queue[i].(*objects.Slider); ok {
+			if danceSlider := newDanceSlider(s, scheduler.rand); danceSlider != nil {
+				scheduler.queue[i] = danceSlider
+			}
+		}
 	}
 
 	// Convert spinners to pseudo spinners that have beginning and ending angles, simplifies mover codes as well
 	for i := 0; i < len(scheduler.queue); i++ {
 		if s, ok := scheduler.queue[i].(*objects.Spinner); ok {
-			scheduler.queue[i] = spinners.NewSpinner(s, spinnerMoverCtor)
+			scheduler.queue[i] = spinners.NewSpinner(s, func() spinners.SpinnerMover {
+				mover := spinnerMoverCtor()
+
+				if r, ok := mover.(movers.RandomMover); ok {
+					r.SetRandom(scheduler.rand)
+				}
+
+				scheduler.spinnerMovers = append(scheduler.spinnerMovers, mover)
+
+				return mover
+			})
 		}
 	}
 
@@ -89,8 +142,25 @@ func (scheduler *GenericScheduler) Init(objs []objects.IHitObject, mods difficul
 	scheduler.queue = scheduler.queue[toRemove:]
 }
 
+// Dispose releases resources of the mover and of spinner movers created so far
+func (scheduler *GenericScheduler) Dispose() {
+	if d, ok := scheduler.mover.(movers.DisposableMover); ok {
+		d.Dispose()
+	}
+
+	for _, mover := range scheduler.spinnerMovers {
+		if d, ok := mover.(movers.DisposableMover); ok {
+			d.Dispose()
+		}
+	}
+
+	scheduler.spinnerMovers = nil
+}
+
 func (scheduler *GenericScheduler) Update(time float64) {
-	if len(scheduler.queue) > 0 {
+	if f, ok := scheduler.mover.(movers.FreeMover); ok && f.IgnoresObjects() {
+		scheduler.cursor.SetPos(scheduler.mover.Update(time))
+	} else if len(scheduler.queue) > 0 {
 		useMover := true
 		lastEndTime := 0.0
 
@@ -0,0 +1,59 @@
+package schedulers
+
+import (
+	"github.com/tsunyoku/danser/app/beatmap/objects"
+	"github.com/tsunyoku/danser/app/dance/movers"
+	"github.com/tsunyoku/danser/app/dance/path"
+	"github.com/tsunyoku/danser/app/graphics"
+	"github.com/tsunyoku/danser/app/settings"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"path/filepath"
+	"testing"
+)
+
+func TestPathReplay(t *testing.T) {
+	exported := path.NewPath(100, 1)
+
+	for i := 0; i < 50; i++ {
+		exported.Add(0, path.Point{Time: float64(i) * 10, X: float32(i) * 7.5, Y: 300 - float32(i)*3.25, Left: i%7 < 3, Right: i%5 == 0})
+	}
+
+	file := filepath.Join(t.TempDir(), "path.csv")
+	if err := exported.Save(file); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	importBefore := settings.Dance.CursorPath.Import
+	settings.Dance.CursorPath.Import = file
+
+	defer func() {
+		settings.Dance.CursorPath.Import = importBefore
+	}()
+
+	scheduler := NewGenericScheduler(movers.NewPathMover, nil).(*GenericScheduler)
+	scheduler.mover.(movers.CursorMover).SetCursor(nil, 0)
+
+	cursor := &graphics.Cursor{}
+
+	// objects placed away from the path must neither snap nor lock the cursor
+	objs := []objects.IHitObject{
+		objects.DummyCircle(vector.NewVec2f(256, 192), 100),
+		objects.DummyCircle(vector.NewVec2f(10, 10), 250),
+	}
+
+	scheduler.cursor = cursor
+	scheduler.queue = objs
+	scheduler.input = scheduler.inputCtor(objs, cursor)
+
+	for _, p := range exported.GetPoints(0) {
+		scheduler.Update(p.Time)
+
+		if cursor.RawPosition.X != p.X || cursor.RawPosition.Y != p.Y {
+			t.Fatalf("time %v: got position %v, want (%v, %v)", p.Time, cursor.RawPosition, p.X, p.Y)
+		}
+
+		if cursor.LeftKey != p.Left || cursor.RightKey != p.Right {
+			t.Fatalf("time %v: got keys %v %v, want %v %v", p.Time, cursor.LeftKey, cursor.RightKey, p.Left, p.Right)
+		}
+	}
+}
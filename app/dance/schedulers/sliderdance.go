@@ -0,0 +1,113 @@
+package schedulers
+
+import (
+	"github.com/tsunyoku/danser/app/beatmap/difficulty"
+	"github.com/tsunyoku/danser/app/beatmap/objects"
+	"github.com/tsunyoku/danser/app/bmath"
+	"github.com/tsunyoku/danser/app/settings"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+// Follow circle is 2.4 times bigger than hit circle while sliding
+const followRadius = 2.4
+
+// sliderStrategy returns offset from slider ball in unit circle, dir is slider's direction at given time
+type sliderStrategy func(time float64, dir vector.Vector2f) vector.Vector2f
+
+var sliderStrategies = map[string]func(frequency float64) sliderStrategy{
+	"orbit": func(frequency float64) sliderStrategy {
+		return func(time float64, _ vector.Vector2f) vector.Vector2f {
+			return vector.NewVec2fRad(float32(time*frequency/1000*2*math.Pi), 1)
+		}
+	},
+	"zigzag": func(frequency float64) sliderStrategy {
+		return func(time float64, dir vector.Vector2f) vector.Vector2f {
+			phase := math.Mod(time*frequency/1000, 1)
+			wave := float32(1 - 4*math.Abs(phase-0.5))
+
+			return vector.NewVec2f(-dir.Y, dir.X).Scl(wave)
+		}
+	},
+	"figure8": func(frequency float64) sliderStrategy {
+		return func(time float64, dir vector.Vector2f) vector.Vector2f {
+			angle := time * frequency / 1000 * 2 * math.Pi
+
+			along := float32(math.Sin(angle))
+			across := float32(math.Sin(2*angle) / 2)
+
+			return dir.Scl(along).Add(vector.NewVec2f(-dir.Y, dir.X).Scl(across))
+		}
+	},
+}
+
+// DanceSlider moves the cursor around slider ball while staying inside the follow circle
+type DanceSlider struct {
+	*objects.Slider
+
+	strategy sliderStrategy
+	radius   float32
+	ramp     float64
+}
+
+// newDanceSlider wraps the slider with strategy set in Dance.SliderStrategy, nil is returned if sliders should be followed normally
+func newDanceSlider(slider *objects.Slider, rng *rand.Rand) *DanceSlider {
+	config := settings.Dance.SliderStrategy
+
+	name := strings.ToLower(config.Type)
+
+	if name == "random" {
+		names := []string{"follow", "orbit", "zigzag", "figure8"}
+		name = names[rng.Intn(len(names))]
+	}
+
+	ctor, ok := sliderStrategies[name]
+	if !ok || slider.GetDifficulty() == nil || slider.IsRetarded() {
+		return nil
+	}
+
+	return &DanceSlider{
+		Slider:   slider,
+		strategy: ctor(config.Frequency),
+		radius:   float32(slider.GetDifficulty().CircleRadius * followRadius * bmath.ClampF64(config.RadiusFactor, 0, 0.95)),
+		ramp:     math.Min(config.RampTime, slider.GetDuration()/4),
+	}
+}
+
+func (slider *DanceSlider) GetStackedPositionAt(time float64) vector.Vector2f {
+	return slider.GetStackedPositionAtMod(time, difficulty.None)
+}
+
+func (slider *DanceSlider) GetStackedPositionAtMod(time float64, modifier difficulty.Modifier) vector.Vector2f {
+	pos := slider.Slider.GetStackedPositionAtMod(time, modifier)
+
+	// Offset fades in and out so the head is still clicked and the next movement starts at the tail
+	envelope := float32(1.0)
+	if slider.ramp > 0 {
+		envelope = float32(bmath.ClampF64(math.Min(time-slider.StartTime, slider.EndTime-time)/slider.ramp, 0, 1))
+	}
+
+	if envelope <= 0 {
+		return pos
+	}
+
+	envelope = envelope * envelope * (3 - 2*envelope)
+
+	dir := slider.Slider.GetStackedPositionAtMod(math.Min(time+1, slider.EndTime), modifier).Sub(slider.Slider.GetStackedPositionAtMod(math.Max(time-1, slider.StartTime), modifier))
+	if dir.Len() < 0.001 {
+		dir = vector.NewVec2f(1, 0)
+	} else {
+		dir = dir.Nor()
+	}
+
+	offset := slider.strategy(time-slider.StartTime, dir)
+
+	// Strategies should stay in unit circle, but make sure the ruleset doesn't drop the slider
+	if l := offset.Len(); l > 1 {
+		offset = offset.Scl(1 / l)
+	}
+
+	return pos.Add(offset.Scl(slider.radius * envelope))
+}
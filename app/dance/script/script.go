@@ -0,0 +1,278 @@
+package script
+
+import (
+	"fmt"
+	"github.com/tsunyoku/danser/app/beatmap/difficulty"
+	"github.com/tsunyoku/danser/app/beatmap/objects"
+	"github.com/tsunyoku/danser/app/settings"
+	lua "github.com/yuin/gopher-lua"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dir is the directory user scripts are loaded from
+const Dir = "movers"
+
+const objectListType = "objects"
+
+// Script is a single Lua state running a user script, it's not safe to use from multiple goroutines
+type Script struct {
+	name  string
+	state *lua.LState
+
+	mods    difficulty.Modifier
+	objects map[objects.IHitObject]*lua.LTable
+	failed  map[string]bool
+}
+
+func getPath(name string) string {
+	return filepath.Join(Dir, strings.ToLower(name)+".lua")
+}
+
+// Exists checks if script with given name exists in Dir
+func Exists(name string) bool {
+	stat, err := os.Stat(getPath(name))
+	return err == nil && !stat.IsDir()
+}
+
+func Load(name string) (*Script, error) {
+	script := &Script{
+		name:    strings.ToLower(name),
+		state:   lua.NewState(lua.Options{SkipOpenLibs: true}),
+		objects: make(map[objects.IHitObject]*lua.LTable),
+		failed:  make(map[string]bool),
+	}
+
+	script.openLibs()
+	script.registerAPI()
+
+	if err := script.state.DoFile(getPath(name)); err != nil {
+		script.state.Close()
+		return nil, err
+	}
+
+	return script, nil
+}
+
+// Close releases the Lua state, script can't be used afterwards
+func (script *Script) Close() {
+	script.state.Close()
+}
+
+// openLibs opens only libraries without file and process access, so scripts can't touch the system
+func (script *Script) openLibs() {
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		script.state.Push(script.state.NewFunction(lib.open))
+		script.state.Push(lua.LString(lib.name))
+		script.state.Call(1, 0)
+	}
+}
+
+func (script *Script) registerAPI() {
+	L := script.state
+
+	danser := L.NewTable()
+	danser.RawSetString("spinner_radius", lua.LNumber(settings.Dance.SpinnerRadius))
+	danser.RawSetString("playfield_width", lua.LNumber(512))
+	danser.RawSetString("playfield_height", lua.LNumber(384))
+
+	L.SetFuncs(danser, map[string]lua.LGFunction{
+		"log": func(L *lua.LState) int {
+			args := make([]interface{}, 0, L.GetTop())
+			for i := 1; i <= L.GetTop(); i++ {
+				args = append(args, L.Get(i).String())
+			}
+
+			log.Println(append([]interface{}{fmt.Sprintf("[%s]", script.name)}, args...)...)
+
+			return 0
+		},
+	})
+
+	L.SetGlobal("danser", danser)
+
+	meta := L.NewTypeMetatable(objectListType)
+	L.SetField(meta, "__len", L.NewFunction(func(L *lua.LState) int {
+		L.Push(lua.LNumber(len(script.checkObjects(L))))
+		return 1
+	}))
+
+	L.SetField(meta, "__index", L.NewFunction(func(L *lua.LState) int {
+		objs := script.checkObjects(L)
+
+		index := L.CheckInt(2)
+		if index < 1 || index > len(objs) {
+			L.Push(lua.LNil)
+		} else {
+			L.Push(script.getObject(objs[index-1]))
+		}
+
+		return 1
+	}))
+}
+
+func (script *Script) checkObjects(L *lua.LState) []objects.IHitObject {
+	ud := L.CheckUserData(1)
+	if objs, ok := ud.Value.([]objects.IHitObject); ok {
+		return objs
+	}
+
+	L.ArgError(1, "object list expected")
+
+	return nil
+}
+
+// getObject converts hit object to a table, tables are cached so scripts can store their own data in them
+func (script *Script) getObject(obj objects.IHitObject) *lua.LTable {
+	if table, ok := script.objects[obj]; ok {
+		return table
+	}
+
+	L := script.state
+	mods := script.mods
+
+	table := L.NewTable()
+
+	startPos := obj.GetStackedStartPositionMod(mods)
+	endPos := obj.GetStackedEndPositionMod(mods)
+
+	table.RawSetString("id", lua.LNumber(obj.GetID()))
+	table.RawSetString("start_time", lua.LNumber(obj.GetStartTime()))
+	table.RawSetString("end_time", lua.LNumber(obj.GetEndTime()))
+	table.RawSetString("duration", lua.LNumber(obj.GetDuration()))
+	table.RawSetString("start_x", lua.LNumber(startPos.X))
+	table.RawSetString("start_y", lua.LNumber(startPos.Y))
+	table.RawSetString("end_x", lua.LNumber(endPos.X))
+	table.RawSetString("end_y", lua.LNumber(endPos.Y))
+	table.RawSetString("new_combo", lua.LBool(obj.IsNewCombo()))
+
+	kind := "circle"
+
+	switch obj.GetType() {
+	case objects.SLIDER:
+		kind = "slider"
+	case objects.SPINNER:
+		kind = "spinner"
+	}
+
+	table.RawSetString("type", lua.LString(kind))
+
+	if long, ok := obj.(objects.ILongObject); ok {
+		table.RawSetString("is_long", lua.LTrue)
+		table.RawSetString("start_angle", lua.LNumber(long.GetStartAngleMod(mods)))
+		table.RawSetString("end_angle", lua.LNumber(long.GetEndAngleMod(mods)))
+		table.RawSetString("part_len", lua.LNumber(long.GetPartLen()))
+	} else {
+		table.RawSetString("is_long", lua.LFalse)
+	}
+
+	table.RawSetString("position_at", L.NewFunction(func(L *lua.LState) int {
+		pos := obj.GetStackedPositionAtMod(float64(L.CheckNumber(1)), script.mods)
+
+		L.Push(lua.LNumber(pos.X))
+		L.Push(lua.LNumber(pos.Y))
+
+		return 2
+	}))
+
+	script.objects[obj] = table
+
+	return table
+}
+
+// SetMods sets mods used for stacked positions and angles, clears object cache
+func (script *Script) SetMods(mods difficulty.Modifier) {
+	script.mods = mods
+	script.objects = make(map[objects.IHitObject]*lua.LTable)
+}
+
+// NewObjectList wraps objects in a lazily converted list indexed from 1, supporting # operator
+func (script *Script) NewObjectList(objs []objects.IHitObject) lua.LValue {
+	ud := script.state.NewUserData()
+	ud.Value = objs
+
+	script.state.SetMetatable(ud, script.state.GetTypeMetatable(objectListType))
+
+	return ud
+}
+
+// SetRandom replaces math.random and math.randomseed so script uses given RNG instead of the global one
+func (script *Script) SetRandom(rng *rand.Rand) {
+	L := script.state
+
+	mathLib, ok := L.GetGlobal("math").(*lua.LTable)
+	if !ok {
+		return
+	}
+
+	L.SetFuncs(mathLib, map[string]lua.LGFunction{
+		"random": func(L *lua.LState) int {
+			switch L.GetTop() {
+			case 0:
+				L.Push(lua.LNumber(rng.Float64()))
+			case 1:
+				L.Push(lua.LNumber(rng.Intn(L.CheckInt(1)) + 1))
+			default:
+				min := L.CheckInt(1)
+				L.Push(lua.LNumber(rng.Intn(L.CheckInt(2)+1-min) + min))
+			}
+
+			return 1
+		},
+		"randomseed": func(L *lua.LState) int {
+			rng.Seed(L.CheckInt64(1))
+			return 0
+		},
+	})
+}
+
+func (script *Script) HasFunction(name string) bool {
+	return script.state.GetGlobal(name).Type() == lua.LTFunction
+}
+
+// Call calls global function and returns its results. Missing functions and errors return nil values, errors are logged once per function
+func (script *Script) Call(name string, results int, args ...lua.LValue) []lua.LValue {
+	ret := make([]lua.LValue, results)
+	for i := range ret {
+		ret[i] = lua.LNil
+	}
+
+	fn := script.state.GetGlobal(name)
+	if fn.Type() != lua.LTFunction || script.failed[name] {
+		return ret
+	}
+
+	if err := script.state.CallByParam(lua.P{Fn: fn, NRet: results, Protect: true}, args...); err != nil {
+		log.Println(fmt.Sprintf("Script \"%s\" failed in %s:", script.name, name), err)
+		script.failed[name] = true
+
+		return ret
+	}
+
+	for i := results - 1; i >= 0; i-- {
+		ret[i] = script.state.Get(-1)
+		script.state.Pop(1)
+	}
+
+	return ret
+}
+
+// Number returns float64 value of Lua number or def if value is not a number
+func Number(value lua.LValue, def float64) float64 {
+	if n, ok := value.(lua.LNumber); ok {
+		return float64(n)
+	}
+
+	return def
+}
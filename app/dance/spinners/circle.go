@@ -10,6 +10,12 @@ type CircleMover struct {
 	start float64
 }
 
+func init() {
+	Register("circle", func() SpinnerMover {
+		return NewCircleMover()
+	})
+}
+
 func NewCircleMover() *CircleMover {
 	return &CircleMover{}
 }
@@ -24,6 +24,12 @@ type CubeMover struct {
 	start float64
 }
 
+func init() {
+	Register("cube", func() SpinnerMover {
+		return NewCubeMover()
+	})
+}
+
 func NewCubeMover() *CubeMover {
 	return &CubeMover{}
 }
@@ -0,0 +1,96 @@
+package spinners
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/tsunyoku/danser/app/dance/spinners/shape"
+	"github.com/tsunyoku/danser/app/settings"
+	"github.com/tsunyoku/danser/framework/math/math32"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"log"
+)
+
+var customShape *shape.Shape
+var customShapeKey string
+
+func init() {
+	Register("custom", func() SpinnerMover {
+		return NewCustomMover()
+	})
+}
+
+// getCustomShape returns shape defined in settings, it's rebuilt only when settings change
+func getCustomShape() *shape.Shape {
+	config := settings.Dance.CustomSpinner
+
+	key := config.SVGPath + "\x00" + config.X + "\x00" + config.Y
+	if customShape != nil && key == customShapeKey {
+		return customShape
+	}
+
+	customShapeKey = key
+	customShape = nil
+
+	var points []vector.Vector2f
+	var err error
+
+	if config.SVGPath != "" {
+		points, err = shape.ParseSVGPath(config.SVGPath)
+	} else {
+		points, err = shape.Parametric(config.X, config.Y)
+	}
+
+	if err == nil {
+		customShape, err = shape.NewShape(points)
+	}
+
+	if err != nil {
+		log.Println("Failed to create custom spinner shape, falling back to circle:", err)
+	}
+
+	return customShape
+}
+
+// CustomMover follows a closed path from SVG path data or parametric formulas set in Dance.CustomSpinner
+type CustomMover struct {
+	start float64
+	shape *shape.Shape
+}
+
+func NewCustomMover() SpinnerMover {
+	customShape := getCustomShape()
+	if customShape == nil {
+		return NewCircleMover()
+	}
+
+	return &CustomMover{shape: customShape}
+}
+
+func (c *CustomMover) Init(start, end float64) {
+	c.start = start
+}
+
+func (c *CustomMover) GetPositionAt(time float64) vector.Vector2f {
+	config := settings.Dance.CustomSpinner
+	radius := float32(settings.Dance.SpinnerRadius)
+
+	pt := c.shape.PointAt(rpms * float32(config.Speed) * float32(time-c.start))
+
+	if !config.Rotate3D {
+		return pt.Scl(radius).Add(center)
+	}
+
+	// Same wobble as CubeMover
+	radY := math32.Sin(float32(time-c.start)/9000*2*math32.Pi) * 3.0 / 18 * math32.Pi
+	radX := math32.Sin(float32(time-c.start)/5000*2*math32.Pi) * 3.0 / 18 * math32.Pi
+
+	scale := (1.0 + math32.Sin(float32(time-c.start)/4500*2*math32.Pi)*0.3) * radius
+
+	mat := mgl32.HomogRotate3DY(radY).Mul4(mgl32.HomogRotate3DX(radX)).Mul4(mgl32.Scale3D(scale, scale, scale))
+
+	pt4 := mat.Mul4x1(mgl32.Vec4{pt.X, pt.Y, 0, 1})
+
+	pt4[0] *= 1 + pt4[2]/scale/10
+	pt4[1] *= 1 + pt4[2]/scale/10
+
+	return vector.NewVec2f(pt4.X(), pt4.Y()).Add(center)
+}
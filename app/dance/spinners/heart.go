@@ -10,6 +10,12 @@ type HeartMover struct {
 	start float64
 }
 
+func init() {
+	Register("heart", func() SpinnerMover {
+		return NewHeartMover()
+	})
+}
+
 func NewHeartMover() *HeartMover {
 	return &HeartMover{}
 }
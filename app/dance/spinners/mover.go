@@ -1,7 +1,9 @@
 package spinners
 
 import (
+	"github.com/tsunyoku/danser/app/dance/script"
 	"github.com/tsunyoku/danser/framework/math/vector"
+	"sort"
 	"strings"
 )
 
@@ -14,19 +16,37 @@ type SpinnerMover interface {
 	GetPositionAt(time float64) vector.Vector2f
 }
 
+var registry = make(map[string]func() SpinnerMover)
+
+// Register adds a spinner mover constructor under given name, names are case-insensitive
+func Register(name string, ctor func() SpinnerMover) {
+	registry[strings.ToLower(name)] = ctor
+}
+
+// GetMoverByName returns a registered spinner mover, then script mover from movers directory, circle mover is used as a fallback
 func GetMoverByName(name string) SpinnerMover {
-	switch strings.ToLower(name) {
-	case "heart":
-		return NewHeartMover()
-	case "triangle":
-		return NewTriangleMover()
-	case "square":
-		return NewSquareMover()
-	case "cube":
-		return NewCubeMover()
-	default:
-		return NewCircleMover()
+	name = strings.ToLower(name)
+
+	if ctor, ok := registry[name]; ok {
+		return ctor()
+	}
+
+	if script.Exists(name) {
+		return NewScriptMover(name)
 	}
+
+	return NewCircleMover()
+}
+
+func GetMoverNames() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
 }
 
 func GetMoverCtorByName(name string) func() SpinnerMover {
@@ -0,0 +1,42 @@
+package spinners
+
+import (
+	"github.com/tsunyoku/danser/app/dance/script"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	lua "github.com/yuin/gopher-lua"
+	"log"
+	"math/rand"
+)
+
+// ScriptMover calls spinner_init and spinner_position_at functions of a Lua script from movers directory
+type ScriptMover struct {
+	script *script.Script
+}
+
+func NewScriptMover(name string) SpinnerMover {
+	s, err := script.Load(name)
+	if err != nil {
+		log.Println("Failed to load spinner script:", err)
+		return NewCircleMover()
+	}
+
+	return &ScriptMover{script: s}
+}
+
+func (c *ScriptMover) Dispose() {
+	c.script.Close()
+}
+
+func (c *ScriptMover) SetRandom(rng *rand.Rand) {
+	c.script.SetRandom(rng)
+}
+
+func (c *ScriptMover) Init(start, end float64) {
+	c.script.Call("spinner_init", 0, lua.LNumber(start), lua.LNumber(end))
+}
+
+func (c *ScriptMover) GetPositionAt(time float64) vector.Vector2f {
+	ret := c.script.Call("spinner_position_at", 2, lua.LNumber(time))
+
+	return vector.NewVec2f(float32(script.Number(ret[0], float64(center.X))), float32(script.Number(ret[1], float64(center.Y))))
+}
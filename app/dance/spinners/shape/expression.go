@@ -0,0 +1,335 @@
+package shape
+
+import (
+	"fmt"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Number of points parametric shapes are sampled with
+const parametricDetail = 720
+
+// Expression is a compiled formula of variable t
+type Expression func(t float64) float64
+
+var constants = map[string]float64{
+	"pi":  math.Pi,
+	"tau": 2 * math.Pi,
+	"e":   math.E,
+}
+
+var functions = map[string]func(args []float64) (float64, error){
+	"sin":   unary(math.Sin),
+	"cos":   unary(math.Cos),
+	"tan":   unary(math.Tan),
+	"asin":  unary(math.Asin),
+	"acos":  unary(math.Acos),
+	"atan":  unary(math.Atan),
+	"sinh":  unary(math.Sinh),
+	"cosh":  unary(math.Cosh),
+	"abs":   unary(math.Abs),
+	"sqrt":  unary(math.Sqrt),
+	"exp":   unary(math.Exp),
+	"log":   unary(math.Log),
+	"floor": unary(math.Floor),
+	"ceil":  unary(math.Ceil),
+	"sign": unary(func(v float64) float64 {
+		if v < 0 {
+			return -1
+		} else if v > 0 {
+			return 1
+		}
+
+		return 0
+	}),
+	"pow":   binary(math.Pow),
+	"atan2": binary(math.Atan2),
+	"mod":   binary(math.Mod),
+	"min":   binary(math.Min),
+	"max":   binary(math.Max),
+}
+
+func unary(f func(float64) float64) func(args []float64) (float64, error) {
+	return func(args []float64) (float64, error) {
+		if len(args) != 1 {
+			return 0, fmt.Errorf("expected 1 argument, got %d", len(args))
+		}
+
+		return f(args[0]), nil
+	}
+}
+
+func binary(f func(float64, float64) float64) func(args []float64) (float64, error) {
+	return func(args []float64) (float64, error) {
+		if len(args) != 2 {
+			return 0, fmt.Errorf("expected 2 arguments, got %d", len(args))
+		}
+
+		return f(args[0], args[1]), nil
+	}
+}
+
+type exprParser struct {
+	data string
+	pos  int
+}
+
+// ParseExpression compiles formula with variable t, constants pi, tau, e, operators + - * / ^ and common math functions
+func ParseExpression(data string) (Expression, error) {
+	parser := &exprParser{data: strings.ToLower(data)}
+
+	expr, err := parser.sum()
+	if err != nil {
+		return nil, err
+	}
+
+	parser.skipSpaces()
+
+	if parser.pos < len(parser.data) {
+		return nil, fmt.Errorf("expression: unexpected '%c' at %d", parser.data[parser.pos], parser.pos)
+	}
+
+	return expr, nil
+}
+
+// Parametric samples closed curve x(t), y(t) for t in [0, 2*pi)
+func Parametric(x, y string) ([]vector.Vector2f, error) {
+	xExpr, err := ParseExpression(x)
+	if err != nil {
+		return nil, err
+	}
+
+	yExpr, err := ParseExpression(y)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]vector.Vector2f, 0, parametricDetail)
+
+	for i := 0; i < parametricDetail; i++ {
+		t := float64(i) / parametricDetail * 2 * math.Pi
+
+		px, py := xExpr(t), yExpr(t)
+
+		if math.IsNaN(px) || math.IsInf(px, 0) || math.IsNaN(py) || math.IsInf(py, 0) {
+			return nil, fmt.Errorf("expression: shape is undefined at t=%.3f", t)
+		}
+
+		points = append(points, vector.NewVec2f(float32(px), float32(py)))
+	}
+
+	return points, nil
+}
+
+func (parser *exprParser) sum() (Expression, error) {
+	left, err := parser.product()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := parser.operator("+-")
+		if op == 0 {
+			return left, nil
+		}
+
+		right, err := parser.product()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+
+		if op == '+' {
+			left = func(t float64) float64 { return l(t) + right(t) }
+		} else {
+			left = func(t float64) float64 { return l(t) - right(t) }
+		}
+	}
+}
+
+func (parser *exprParser) product() (Expression, error) {
+	left, err := parser.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := parser.operator("*/%")
+		if op == 0 {
+			return left, nil
+		}
+
+		right, err := parser.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+
+		switch op {
+		case '*':
+			left = func(t float64) float64 { return l(t) * right(t) }
+		case '/':
+			left = func(t float64) float64 { return l(t) / right(t) }
+		default:
+			left = func(t float64) float64 { return math.Mod(l(t), right(t)) }
+		}
+	}
+}
+
+func (parser *exprParser) unary() (Expression, error) {
+	switch parser.operator("+-") {
+	case '-':
+		expr, err := parser.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(t float64) float64 { return -expr(t) }, nil
+	case '+':
+		return parser.unary()
+	}
+
+	return parser.power()
+}
+
+func (parser *exprParser) power() (Expression, error) {
+	base, err := parser.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	if parser.operator("^") == 0 {
+		return base, nil
+	}
+
+	// Right associative and binds stronger than unary minus on the left: -2^2 = -4
+	exponent, err := parser.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(t float64) float64 { return math.Pow(base(t), exponent(t)) }, nil
+}
+
+func (parser *exprParser) primary() (Expression, error) {
+	parser.skipSpaces()
+
+	if parser.pos >= len(parser.data) {
+		return nil, fmt.Errorf("expression: unexpected end")
+	}
+
+	c := parser.data[parser.pos]
+
+	switch {
+	case c == '(':
+		parser.pos++
+
+		expr, err := parser.sum()
+		if err != nil {
+			return nil, err
+		}
+
+		if parser.operator(")") == 0 {
+			return nil, fmt.Errorf("expression: missing ')' at %d", parser.pos)
+		}
+
+		return expr, nil
+	case c >= '0' && c <= '9' || c == '.':
+		start := parser.pos
+
+		for parser.pos < len(parser.data) && (parser.data[parser.pos] >= '0' && parser.data[parser.pos] <= '9' || parser.data[parser.pos] == '.') {
+			parser.pos++
+		}
+
+		v, err := strconv.ParseFloat(parser.data[start:parser.pos], 64)
+		if err != nil {
+			return nil, fmt.Errorf("expression: invalid number at %d", start)
+		}
+
+		return func(float64) float64 { return v }, nil
+	case unicode.IsLetter(rune(c)):
+		start := parser.pos
+
+		for parser.pos < len(parser.data) && (unicode.IsLetter(rune(parser.data[parser.pos])) || unicode.IsDigit(rune(parser.data[parser.pos]))) {
+			parser.pos++
+		}
+
+		name := parser.data[start:parser.pos]
+
+		if name == "t" {
+			return func(t float64) float64 { return t }, nil
+		}
+
+		if v, ok := constants[name]; ok {
+			return func(float64) float64 { return v }, nil
+		}
+
+		if f, ok := functions[name]; ok {
+			return parser.call(name, f)
+		}
+
+		return nil, fmt.Errorf("expression: unknown identifier \"%s\" at %d", name, start)
+	}
+
+	return nil, fmt.Errorf("expression: unexpected '%c' at %d", c, parser.pos)
+}
+
+func (parser *exprParser) call(name string, f func(args []float64) (float64, error)) (Expression, error) {
+	if parser.operator("(") == 0 {
+		return nil, fmt.Errorf("expression: expected '(' after %s", name)
+	}
+
+	var args []Expression
+
+	for parser.operator(")") == 0 {
+		if len(args) > 0 && parser.operator(",") == 0 {
+			return nil, fmt.Errorf("expression: expected ',' at %d", parser.pos)
+		}
+
+		arg, err := parser.sum()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+	}
+
+	// Validate argument count once instead of on every evaluation
+	if _, err := f(make([]float64, len(args))); err != nil {
+		return nil, fmt.Errorf("expression: %s: %w", name, err)
+	}
+
+	return func(t float64) float64 {
+		values := make([]float64, len(args))
+		for i, arg := range args {
+			values[i] = arg(t)
+		}
+
+		v, _ := f(values)
+
+		return v
+	}, nil
+}
+
+// operator consumes and returns the next character if it's one of ops, 0 otherwise
+func (parser *exprParser) operator(ops string) byte {
+	parser.skipSpaces()
+
+	if parser.pos < len(parser.data) && strings.IndexByte(ops, parser.data[parser.pos]) >= 0 {
+		parser.pos++
+		return parser.data[parser.pos-1]
+	}
+
+	return 0
+}
+
+func (parser *exprParser) skipSpaces() {
+	for parser.pos < len(parser.data) && unicode.IsSpace(rune(parser.data[parser.pos])) {
+		parser.pos++
+	}
+}
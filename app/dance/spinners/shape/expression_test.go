@@ -0,0 +1,70 @@
+package shape
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		data     string
+		t        float64
+		expected float64
+	}{
+		{"1 + 2 * 3", 0, 7},
+		{"(1 + 2) * 3", 0, 9},
+		{"2 ^ 3 ^ 2", 0, 512},
+		{"-2 ^ 2", 0, -4},
+		{"10 / 4 - 1", 0, 1.5},
+		{"t * 2", 1.5, 3},
+		{"sin(t)", math.Pi / 2, 1},
+		{"cos(pi)", 0, -1},
+		{"tau / 2", 0, math.Pi},
+		{"pow(2, 10)", 0, 1024},
+		{"max(t, 3) + min(t, 3)", 5, 8},
+		{"atan2(1, 1)", 0, math.Pi / 4},
+		{"SQRT(16) + E - e", 0, 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.data, func(t *testing.T) {
+			expr, err := ParseExpression(test.data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if v := expr(test.t); math.Abs(v-test.expected) > 1e-9 {
+				t.Errorf("got %v, want %v", v, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	for _, data := range []string{"", "1 +", "(1 + 2", "foo(1)", "x", "sin(1, 2)", "pow(1)", "1 2"} {
+		t.Run(data, func(t *testing.T) {
+			if _, err := ParseExpression(data); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestParametricNonFinite(t *testing.T) {
+	if _, err := Parametric("sqrt(sin(t))", "cos(t)"); err == nil {
+		t.Error("expected an error for NaN points")
+	}
+
+	if _, err := Parametric("1 / (t - pi)", "0"); err == nil {
+		t.Error("expected an error for infinite points")
+	}
+
+	points, err := Parametric("cos(t)", "sin(t)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = NewShape(points); err != nil {
+		t.Errorf("circle should be a valid shape: %v", err)
+	}
+}
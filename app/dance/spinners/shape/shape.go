@@ -0,0 +1,101 @@
+package shape
+
+import (
+	"errors"
+	"github.com/tsunyoku/danser/framework/math/math32"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"math"
+	"sort"
+)
+
+// Shape is a closed polyline normalized to fit in [-1, 1] box, parametrized by arc length
+type Shape struct {
+	points  []vector.Vector2f
+	lengths []float32
+}
+
+// NewShape centers and scales given points, the path is closed automatically
+func NewShape(points []vector.Vector2f) (*Shape, error) {
+	if len(points) < 2 {
+		return nil, errors.New("shape needs at least 2 points")
+	}
+
+	minX, minY := math32.Inf(1), math32.Inf(1)
+	maxX, maxY := math32.Inf(-1), math32.Inf(-1)
+
+	for _, p := range points {
+		if !isFinite(p.X) || !isFinite(p.Y) {
+			return nil, errors.New("shape has non-finite points")
+		}
+
+		minX, minY = math32.Min(minX, p.X), math32.Min(minY, p.Y)
+		maxX, maxY = math32.Max(maxX, p.X), math32.Max(maxY, p.Y)
+	}
+
+	halfSize := math32.Max(maxX-minX, maxY-minY) / 2
+	if halfSize == 0 {
+		return nil, errors.New("shape has no size")
+	}
+
+	if !isFinite(halfSize) {
+		return nil, errors.New("shape is too big")
+	}
+
+	centre := vector.NewVec2f((minX+maxX)/2, (minY+maxY)/2)
+
+	shape := &Shape{
+		points:  make([]vector.Vector2f, 0, len(points)+1),
+		lengths: make([]float32, 0, len(points)+1),
+	}
+
+	for _, p := range points {
+		shape.add(p.Sub(centre).Scl(1 / halfSize))
+	}
+
+	if shape.points[0] != shape.points[len(shape.points)-1] {
+		shape.add(shape.points[0])
+	}
+
+	if shape.lengths[len(shape.lengths)-1] == 0 {
+		return nil, errors.New("shape has no length")
+	}
+
+	return shape, nil
+}
+
+func isFinite(v float32) bool {
+	return !math.IsNaN(float64(v)) && !math.IsInf(float64(v), 0)
+}
+
+func (shape *Shape) add(p vector.Vector2f) {
+	length := float32(0)
+
+	if len(shape.points) > 0 {
+		length = shape.lengths[len(shape.lengths)-1] + shape.points[len(shape.points)-1].Dst(p)
+	}
+
+	shape.points = append(shape.points, p)
+	shape.lengths = append(shape.lengths, length)
+}
+
+// PointAt returns point at given fraction of path's length, values outside [0, 1) wrap around
+func (shape *Shape) PointAt(t float32) vector.Vector2f {
+	t -= math32.Floor(t)
+
+	length := t * shape.lengths[len(shape.lengths)-1]
+
+	index := sort.Search(len(shape.lengths), func(i int) bool {
+		return shape.lengths[i] >= length
+	})
+
+	if index == 0 {
+		return shape.points[0]
+	}
+
+	segment := shape.lengths[index] - shape.lengths[index-1]
+	if segment == 0 {
+		return shape.points[index]
+	}
+
+	return shape.points[index-1].Lerp(shape.points[index], (length-shape.lengths[index-1])/segment)
+}
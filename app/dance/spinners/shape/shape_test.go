@@ -0,0 +1,52 @@
+package shape
+
+import (
+	"github.com/tsunyoku/danser/framework/math/math32"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"testing"
+)
+
+func TestNewShapeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []vector.Vector2f
+	}{
+		{"single point", []vector.Vector2f{{X: 1, Y: 1}}},
+		{"no size", []vector.Vector2f{{X: 1, Y: 1}, {X: 1, Y: 1}}},
+		{"NaN", []vector.Vector2f{{X: 0, Y: 0}, {X: math32.NaN(), Y: 1}, {X: 1, Y: 1}}},
+		{"infinity", []vector.Vector2f{{X: 0, Y: 0}, {X: math32.Inf(1), Y: 1}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewShape(test.points); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestPointAt(t *testing.T) {
+	shape, err := NewShape([]vector.Vector2f{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		t        float32
+		expected vector.Vector2f
+	}{
+		{0, vector.NewVec2f(-1, -1)},
+		{0.125, vector.NewVec2f(0, -1)},
+		{0.25, vector.NewVec2f(1, -1)},
+		{0.5, vector.NewVec2f(1, 1)},
+		{1.75, vector.NewVec2f(-1, 1)},
+		{-0.25, vector.NewVec2f(-1, 1)},
+	}
+
+	for _, test := range tests {
+		if p := shape.PointAt(test.t); p.Dst(test.expected) > 0.0001 {
+			t.Errorf("PointAt(%v): got %v, want %v", test.t, p, test.expected)
+		}
+	}
+}
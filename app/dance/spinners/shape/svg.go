@@ -0,0 +1,353 @@
+package shape
+
+import (
+	"fmt"
+	"github.com/tsunyoku/danser/framework/math/curves"
+	"github.com/tsunyoku/danser/framework/math/math32"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Number of points each curve segment is flattened to
+const curveDetail = 16
+
+type svgParser struct {
+	data  string
+	pos   int
+	cmd   byte
+	start vector.Vector2f
+	pen   vector.Vector2f
+	ctrl  vector.Vector2f // last control point, used by smooth curves
+
+	points []vector.Vector2f
+}
+
+// ParseSVGPath flattens SVG path data (the "d" attribute) to a list of points
+func ParseSVGPath(data string) ([]vector.Vector2f, error) {
+	parser := &svgParser{data: data}
+
+	if err := parser.parse(); err != nil {
+		return nil, err
+	}
+
+	return parser.points, nil
+}
+
+func (parser *svgParser) parse() error {
+	for {
+		parser.skipSeparators()
+
+		if parser.pos >= len(parser.data) {
+			return nil
+		}
+
+		c := parser.data[parser.pos]
+
+		if strings.IndexByte("MmLlHhVvCcSsQqTtAaZz", c) >= 0 {
+			parser.cmd = c
+			parser.pos++
+		} else if parser.cmd == 0 {
+			return fmt.Errorf("svg path: expected command at %d", parser.pos)
+		}
+
+		if err := parser.command(); err != nil {
+			return err
+		}
+	}
+}
+
+func (parser *svgParser) command() error {
+	relative := unicode.IsLower(rune(parser.cmd))
+
+	switch unicode.ToUpper(rune(parser.cmd)) {
+	case 'Z':
+		parser.lineTo(parser.start)
+		parser.ctrl = parser.pen
+
+		// Z doesn't take arguments, avoid repeating it on numbers
+		parser.cmd = 0
+
+		return nil
+	case 'M':
+		p, err := parser.point(relative)
+		if err != nil {
+			return err
+		}
+
+		parser.pen, parser.start, parser.ctrl = p, p, p
+		parser.points = append(parser.points, p)
+
+		// Next coordinate pairs are implicit line commands
+		if relative {
+			parser.cmd = 'l'
+		} else {
+			parser.cmd = 'L'
+		}
+	case 'L', 'T':
+		p, err := parser.point(relative)
+		if err != nil {
+			return err
+		}
+
+		if parser.cmd == 'L' || parser.cmd == 'l' {
+			parser.lineTo(p)
+			parser.ctrl = p
+		} else {
+			ctrl := parser.pen.Mult(vector.NewVec2f(2, 2)).Sub(parser.ctrl)
+			parser.curveTo(ctrl, p)
+			parser.ctrl = ctrl
+		}
+	case 'H', 'V':
+		v, err := parser.number()
+		if err != nil {
+			return err
+		}
+
+		p := parser.pen
+
+		horizontal := parser.cmd == 'H' || parser.cmd == 'h'
+
+		switch {
+		case horizontal && relative:
+			p.X += v
+		case horizontal:
+			p.X = v
+		case relative:
+			p.Y += v
+		default:
+			p.Y = v
+		}
+
+		parser.lineTo(p)
+		parser.ctrl = p
+	case 'C', 'S':
+		var ctrl1 vector.Vector2f
+		var err error
+
+		smooth := parser.cmd == 'S' || parser.cmd == 's'
+
+		if smooth {
+			ctrl1 = parser.pen.Mult(vector.NewVec2f(2, 2)).Sub(parser.ctrl)
+		} else if ctrl1, err = parser.point(relative); err != nil {
+			return err
+		}
+
+		ctrl2, err := parser.point(relative)
+		if err != nil {
+			return err
+		}
+
+		p, err := parser.point(relative)
+		if err != nil {
+			return err
+		}
+
+		parser.curveTo(ctrl1, ctrl2, p)
+		parser.ctrl = ctrl2
+	case 'Q':
+		ctrl, err := parser.point(relative)
+		if err != nil {
+			return err
+		}
+
+		p, err := parser.point(relative)
+		if err != nil {
+			return err
+		}
+
+		parser.curveTo(ctrl, p)
+		parser.ctrl = ctrl
+	case 'A':
+		var args [3]float32
+
+		for i := range args {
+			v, err := parser.number()
+			if err != nil {
+				return err
+			}
+
+			args[i] = v
+		}
+
+		largeArc, err := parser.flag()
+		if err != nil {
+			return err
+		}
+
+		sweep, err := parser.flag()
+		if err != nil {
+			return err
+		}
+
+		p, err := parser.point(relative)
+		if err != nil {
+			return err
+		}
+
+		parser.arcTo(args[0], args[1], args[2], largeArc, sweep, p)
+		parser.ctrl = p
+	}
+
+	return nil
+}
+
+func (parser *svgParser) lineTo(p vector.Vector2f) {
+	parser.points = append(parser.points, p)
+	parser.pen = p
+}
+
+func (parser *svgParser) curveTo(points ...vector.Vector2f) {
+	bezier := curves.NewBezierNA(append([]vector.Vector2f{parser.pen}, points...))
+
+	for i := 1; i <= curveDetail; i++ {
+		parser.points = append(parser.points, bezier.PointAt(float32(i)/curveDetail))
+	}
+
+	parser.pen = points[len(points)-1]
+}
+
+// arcTo flattens elliptical arc using endpoint to center parametrization conversion from SVG spec
+func (parser *svgParser) arcTo(rx, ry, rotation float32, largeArc, sweep bool, p vector.Vector2f) {
+	rx, ry = math32.Abs(rx), math32.Abs(ry)
+
+	if rx == 0 || ry == 0 || parser.pen == p {
+		parser.lineTo(p)
+		return
+	}
+
+	phi := float64(rotation) * math.Pi / 180
+	sinPhi, cosPhi := math.Sincos(phi)
+
+	dx := float64(parser.pen.X-p.X) / 2
+	dy := float64(parser.pen.Y-p.Y) / 2
+
+	x1 := cosPhi*dx + sinPhi*dy
+	y1 := -sinPhi*dx + cosPhi*dy
+
+	rX, rY := float64(rx), float64(ry)
+
+	// Scale up radii if they are too small to reach the end point
+	if lambda := x1*x1/(rX*rX) + y1*y1/(rY*rY); lambda > 1 {
+		rX *= math.Sqrt(lambda)
+		rY *= math.Sqrt(lambda)
+	}
+
+	num := rX*rX*rY*rY - rX*rX*y1*y1 - rY*rY*x1*x1
+	den := rX*rX*y1*y1 + rY*rY*x1*x1
+
+	coef := math.Sqrt(math.Max(0, num/den))
+	if largeArc == sweep {
+		coef = -coef
+	}
+
+	cx1 := coef * rX * y1 / rY
+	cy1 := -coef * rY * x1 / rX
+
+	cx := cosPhi*cx1 - sinPhi*cy1 + float64(parser.pen.X+p.X)/2
+	cy := sinPhi*cx1 + cosPhi*cy1 + float64(parser.pen.Y+p.Y)/2
+
+	theta1 := math.Atan2((y1-cy1)/rY, (x1-cx1)/rX)
+	delta := math.Atan2((-y1-cy1)/rY, (-x1-cx1)/rX) - theta1
+
+	if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	} else if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	}
+
+	for i := 1; i <= curveDetail; i++ {
+		sinT, cosT := math.Sincos(theta1 + delta*float64(i)/curveDetail)
+
+		x := cosPhi*rX*cosT - sinPhi*rY*sinT + cx
+		y := sinPhi*rX*cosT + cosPhi*rY*sinT + cy
+
+		parser.points = append(parser.points, vector.NewVec2f(float32(x), float32(y)))
+	}
+
+	parser.points[len(parser.points)-1] = p
+	parser.pen = p
+}
+
+func (parser *svgParser) point(relative bool) (vector.Vector2f, error) {
+	x, err := parser.number()
+	if err != nil {
+		return vector.Vector2f{}, err
+	}
+
+	y, err := parser.number()
+	if err != nil {
+		return vector.Vector2f{}, err
+	}
+
+	p := vector.NewVec2f(x, y)
+	if relative {
+		p = p.Add(parser.pen)
+	}
+
+	return p, nil
+}
+
+func (parser *svgParser) number() (float32, error) {
+	parser.skipSeparators()
+
+	start := parser.pos
+
+	if parser.pos < len(parser.data) && (parser.data[parser.pos] == '-' || parser.data[parser.pos] == '+') {
+		parser.pos++
+	}
+
+	dot, exp := false, false
+
+	for ; parser.pos < len(parser.data); parser.pos++ {
+		c := parser.data[parser.pos]
+
+		switch {
+		case c >= '0' && c <= '9':
+		case c == '.' && !dot && !exp:
+			dot = true
+		case (c == 'e' || c == 'E') && !exp:
+			exp = true
+
+			if parser.pos+1 < len(parser.data) && (parser.data[parser.pos+1] == '-' || parser.data[parser.pos+1] == '+') {
+				parser.pos++
+			}
+		default:
+			return parser.parseFloat(start)
+		}
+	}
+
+	return parser.parseFloat(start)
+}
+
+// flag reads an arc flag, flags are single characters so they may be written without separators like "a5 5 0 01-5 5"
+func (parser *svgParser) flag() (bool, error) {
+	parser.skipSeparators()
+
+	if parser.pos < len(parser.data) {
+		switch parser.data[parser.pos] {
+		case '0', '1':
+			parser.pos++
+			return parser.data[parser.pos-1] == '1', nil
+		}
+	}
+
+	return false, fmt.Errorf("svg path: invalid arc flag at %d", parser.pos)
+}
+
+func (parser *svgParser) parseFloat(start int) (float32, error) {
+	v, err := strconv.ParseFloat(parser.data[start:parser.pos], 32)
+	if err != nil {
+		return 0, fmt.Errorf("svg path: invalid number at %d", start)
+	}
+
+	return float32(v), nil
+}
+
+func (parser *svgParser) skipSeparators() {
+	for parser.pos < len(parser.data) && (unicode.IsSpace(rune(parser.data[parser.pos])) || parser.data[parser.pos] == ',') {
+		parser.pos++
+	}
+}
@@ -0,0 +1,63 @@
+package shape
+
+import (
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"testing"
+)
+
+func TestParseSVGPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		count int
+		last  vector.Vector2f
+	}{
+		{"lines", "M0 0 L10 0 L10 10 Z", 4, vector.NewVec2f(0, 0)},
+		{"implicit lines", "M0,0 10,0 10,10", 3, vector.NewVec2f(10, 10)},
+		{"relative", "m5 5 l10 0 h5 v-5", 4, vector.NewVec2f(20, 0)},
+		{"compact numbers", "M0-5L.5.5-1e1,2", 3, vector.NewVec2f(-10, 2)},
+		{"cubic", "M0 0 C0 10 10 10 10 0", 1 + curveDetail, vector.NewVec2f(10, 0)},
+		{"smooth quadratic", "M0 0 Q5 10 10 0 T20 0", 1 + 2*curveDetail, vector.NewVec2f(20, 0)},
+		{"arc", "M0 0 A5 5 0 0 1 10 0", 1 + curveDetail, vector.NewVec2f(10, 0)},
+		{"compact arc flags", "M0 0 a5 5 0 01-5 5", 1 + curveDetail, vector.NewVec2f(-5, 5)},
+		{"compact arc flags without spaces", "M0 0a5 5 0 1110 0", 1 + curveDetail, vector.NewVec2f(10, 0)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			points, err := ParseSVGPath(test.data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(points) != test.count {
+				t.Errorf("got %d points, want %d", len(points), test.count)
+			}
+
+			if last := points[len(points)-1]; last.Dst(test.last) > 0.001 {
+				t.Errorf("got last point %v, want %v", last, test.last)
+			}
+		})
+	}
+}
+
+func TestParseSVGPathErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"no command", "0 0 10 10"},
+		{"missing coordinate", "M0 0 L10"},
+		{"invalid number", "M0 0 L1e 5"},
+		{"invalid arc flag", "M0 0 a5 5 0 2 1 10 0"},
+		{"missing arc flag", "M0 0 a5 5 0 1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseSVGPath(test.data); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -13,6 +13,12 @@ type SquareMover struct {
 	start float64
 }
 
+func init() {
+	Register("square", func() SpinnerMover {
+		return NewSquareMover()
+	})
+}
+
 func NewSquareMover() *SquareMover {
 	return &SquareMover{}
 }
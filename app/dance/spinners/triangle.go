@@ -13,6 +13,12 @@ type TriangleMover struct {
 	start float64
 }
 
+func init() {
+	Register("triangle", func() SpinnerMover {
+		return NewTriangleMover()
+	})
+}
+
 func NewTriangleMover() *TriangleMover {
 	return &TriangleMover{}
 }
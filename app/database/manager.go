@@ -211,6 +211,22 @@ func importMaps() {
 				})
 			}
 
+			// Packed sets left in Songs folder are read in place
+			if !de.IsDir() && beatmap.IsArchive(de.Name()) && filepath.Dir(osPathname) == songsDir {
+				fileMap := utils.NewFileMap(osPathname)
+				names := fileMap.GetFiles()
+				fileMap.Close()
+
+				for _, name := range names {
+					if strings.HasSuffix(name, ".osu") && !strings.Contains(name, "/") {
+						candidates = append(candidates, mapLocation{
+							dir:  de.Name(),
+							file: name,
+						})
+					}
+				}
+			}
+
 			return nil
 		},
 		Unsorted: true,
@@ -227,9 +243,8 @@ func importMaps() {
 
 	for _, candidate := range candidates {
 		partialPath := filepath.Join(candidate.dir, candidate.file)
-		mapPath := filepath.Join(songsDir, partialPath)
 
-		stat, err := os.Stat(mapPath)
+		stat, err := os.Stat(getStatPath(candidate))
 		if err != nil {
 			log.Println("DatabaseManager: Failed to read file stats, skipping:", partialPath)
 			log.Println("DatabaseManager: Error:", err)
@@ -281,9 +296,8 @@ func importMaps() {
 			candidate := a.(mapLocation)
 
 			partialPath := filepath.Join(candidate.dir, candidate.file)
-			mapPath := filepath.Join(songsDir, partialPath)
 
-			file, err := os.Open(mapPath)
+			file, err := beatmap.OpenFile(candidate.dir, candidate.file)
 			if err != nil {
 				log.Println(fmt.Sprintf("\"DatabaseManager: Failed to read \"%s\", skipping. Error: %s", partialPath, err))
 				return nil
@@ -293,9 +307,11 @@ func importMaps() {
 
 			log.Println("DatabaseManager: Importing:", partialPath)
 
-			if bMap := beatmap.ParseBeatMapFile(file); bMap != nil {
-				stat, _ := file.Stat()
-				bMap.LastModified = stat.ModTime().UnixNano() / 1000000
+			if bMap := beatmap.LoadBeatMap(candidate.dir, candidate.file); bMap != nil {
+				if stat, err := os.Stat(getStatPath(candidate)); err == nil {
+					bMap.LastModified = stat.ModTime().UnixNano() / 1000000
+				}
+
 				bMap.TimeAdded = time.Now().UnixNano() / 1000000
 
 				hash := md5.New()
@@ -385,7 +401,7 @@ func migrateBeatmaps() {
 			toUpdate := make([]*beatmap.BeatMap, 0)
 
 			for location := range lastModified {
-				file, err := os.Open(filepath.Join(songsDir, location.dir, location.file))
+				file, err := beatmap.OpenFile(location.dir, location.file)
 				if err != nil {
 					log.Println("Failed to open file, removing from database:", location.file)
 					log.Println("Error:", err)
@@ -395,7 +411,9 @@ func migrateBeatmaps() {
 					continue
 				}
 
-				bMap := beatmap.ParseBeatMapFile(file)
+				file.Close()
+
+				bMap := beatmap.LoadBeatMap(location.dir, location.file)
 				if bMap == nil {
 					log.Println("Corrupted cached beatmap found. Removing from database:", location.file)
 
@@ -584,6 +602,15 @@ func loadBeatmapsFromDatabase() []*beatmap.BeatMap {
 	return beatmaps
 }
 
+// getStatPath returns the path which modification time is tracked, for archived sets it's the .osz itself
+func getStatPath(location mapLocation) string {
+	if beatmap.IsArchive(location.dir) {
+		return filepath.Join(songsDir, location.dir)
+	}
+
+	return filepath.Join(songsDir, location.dir, location.file)
+}
+
 func getLastModified() map[mapLocation]int64 {
 	res, _ := dbFile.Query("SELECT dir, file, lastModified FROM beatmaps")
 
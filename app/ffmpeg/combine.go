@@ -1,7 +1,9 @@
 package ffmpeg
 
 import (
+	"fmt"
 	"github.com/tsunyoku/danser/app/settings"
+	"github.com/tsunyoku/danser/framework/bass"
 	"log"
 	"os"
 	"os/exec"
@@ -10,7 +12,8 @@ import (
 	"time"
 )
 
-func Combine(output string) {
+// Combine muxes recorded video and audio, stems are added as additional, titled audio tracks
+func Combine(output string, stems []bass.Stem) {
 	if strings.TrimSpace(output) == "" {
 		output = "danser_" + time.Now().Format("2006-01-02_15-04-05")
 	}
@@ -19,9 +22,23 @@ func Combine(output string) {
 		"-y",
 		"-i", filepath.Join(settings.Recording.OutputDir, filename+"."+settings.Recording.Container),
 		"-i", filepath.Join(settings.Recording.OutputDir, filename+".wav"),
-		"-c:v", "copy",
 	}
 
+	for _, stem := range stems {
+		options = append(options, "-i", stem.Path)
+	}
+
+	options = append(options, "-map", "0:v", "-map", "1:a")
+
+	for i, stem := range stems {
+		options = append(options,
+			"-map", fmt.Sprintf("%d:a", i+2),
+			fmt.Sprintf("-metadata:s:a:%d", i+1), "title="+stem.Name,
+		)
+	}
+
+	options = append(options, "-c:v", "copy")
+
 	filters := strings.TrimSpace(settings.Recording.AudioFilters)
 	if len(filters) > 0 {
 		options = append(options, "-af", filters)
@@ -59,5 +76,9 @@ func Combine(output string) {
 	_ = os.Remove(filepath.Join(settings.Recording.OutputDir, filename+"."+settings.Recording.Container))
 	_ = os.Remove(filepath.Join(settings.Recording.OutputDir, filename+".wav"))
 
+	for _, stem := range stems {
+		_ = os.Remove(stem.Path)
+	}
+
 	log.Println("Finished.")
 }
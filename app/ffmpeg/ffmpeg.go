@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/faiface/mainthread"
 	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/tsunyoku/danser/app/bmath"
 	"github.com/tsunyoku/danser/app/settings"
 	"github.com/tsunyoku/danser/framework/graphics/effects"
 	"io"
@@ -58,6 +59,10 @@ var pboPool = make([]*PBO, 0)
 var syncPool = make([]*PBO, 0)
 
 var blend *effects.Blend
+var blendWeights = make(map[int][]float32)
+
+var shutter *Shutter
+var subFrame int
 
 // check used encoders exist
 func precheck() {
@@ -127,10 +132,14 @@ func StartFFmpeg(fps, _w, _h int) {
 		filters = "," + filters
 	}
 
-	if settings.Recording.MotionBlur.Enabled {
-		fps /= settings.Recording.MotionBlur.OversampleMultiplier
+	if GetRenderFPS() < fps {
+		filters = fmt.Sprintf(",minterpolate=fps=%d:mi_mode=%s", fps, settings.Recording.Interpolation.Mode) + filters
 	}
 
+	fps = GetRenderFPS()
+
+	shutter = NewShutter(float64(fps))
+
 	options := []string{
 		"-y", //(optional) overwrite output file if it exists
 		"-f", "rawvideo",
@@ -207,6 +216,17 @@ func StartFFmpeg(fps, _w, _h int) {
 	}()
 }
 
+// GetRenderFPS returns the number of frames per second that have to be rendered, if interpolation is enabled ffmpeg synthesises the rest
+func GetRenderFPS() int {
+	fps := settings.Recording.FPS
+
+	if settings.Recording.Interpolation.Enabled && settings.Recording.Interpolation.RenderFPS > 0 {
+		fps = bmath.MinI(fps, settings.Recording.Interpolation.RenderFPS)
+	}
+
+	return fps
+}
+
 func StopFFmpeg() {
 	log.Println("Finishing rendering...")
 
@@ -228,27 +248,49 @@ func StopFFmpeg() {
 	log.Println("Ffmpeg finished.")
 }
 
+// NextFrame schedules the next output frame, returns capture times of its subframes in milliseconds from the beginning of recording
+func NextFrame(cursorSpeed float64) []float64 {
+	subFrame = 0
+
+	return shutter.NextFrame(cursorSpeed)
+}
+
 func PreFrame() {
 	if settings.Recording.MotionBlur.Enabled {
 		blend.Begin()
 	}
 }
 
-var frameNumber = int64(-1)
-
-func MakeFrame() {
-	frameNumber++
+// EndSubFrame finishes current subframe. If it was the last subframe of the output frame,
+// subframes are blended into currently bound framebuffer and true is returned
+func EndSubFrame() bool {
+	subFrame++
 
 	if settings.Recording.MotionBlur.Enabled {
 		blend.End()
 
-		if frameNumber%int64(settings.Recording.MotionBlur.OversampleMultiplier) != 0 {
-			return
+		if subFrame < shutter.SubFrames() {
+			return false
 		}
 
-		blend.Blend()
+		blend.BlendWeighted(getWeights(blendCount(shutter.SubFrames())))
 	}
 
+	return true
+}
+
+func getWeights(count int) []float32 {
+	if weights, ok := blendWeights[count]; ok {
+		return weights
+	}
+
+	weights := calculateWeights(count)
+	blendWeights[count] = weights
+
+	return weights
+}
+
+func MakeFrame() {
 	//spin until at least one pbo is free
 	for len(pboPool) == 0 {
 		CheckData()
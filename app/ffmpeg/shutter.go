@@ -0,0 +1,78 @@
+package ffmpeg
+
+import (
+	"github.com/tsunyoku/danser/app/bmath"
+	"github.com/tsunyoku/danser/app/settings"
+	"math"
+)
+
+// Shutter schedules the moments at which subframes of every output frame are rendered
+type Shutter struct {
+	frameTime float64
+	frame     int64
+
+	samples []float64
+}
+
+func NewShutter(fps float64) *Shutter {
+	return &Shutter{
+		frameTime: 1000 / fps,
+	}
+}
+
+// NextFrame advances to the next output frame and returns the times (in ms, relative to recording start) at which its subframes should be captured.
+// With ShutterPhase at 0 the last returned time is the moment of output frame itself. cursorSpeed is expressed in osu!pixels per millisecond.
+func (shutter *Shutter) NextFrame(cursorSpeed float64) []float64 {
+	shutter.frame++
+
+	frameEnd := float64(shutter.frame) * shutter.frameTime
+
+	if !settings.Recording.MotionBlur.Enabled {
+		shutter.samples = append(shutter.samples[:0], frameEnd)
+		return shutter.samples
+	}
+
+	count := shutter.getSubFrames(cursorSpeed)
+
+	open := shutter.frameTime * bmath.ClampF64(settings.Recording.MotionBlur.ShutterAngle, 1, 360) / 360
+	phase := shutter.frameTime * math.Mod(settings.Recording.MotionBlur.ShutterPhase, 360) / 360
+
+	start := frameEnd - open + phase
+
+	shutter.samples = shutter.samples[:0]
+
+	for i := 1; i <= count; i++ {
+		shutter.samples = append(shutter.samples, math.Max(0, start+open*float64(i)/float64(count)))
+	}
+
+	return shutter.samples
+}
+
+// SubFrames returns how many subframes were scheduled for current output frame
+func (shutter *Shutter) SubFrames() int {
+	return len(shutter.samples)
+}
+
+func (shutter *Shutter) getSubFrames(cursorSpeed float64) int {
+	maxCount := bmath.MaxI(1, settings.Recording.MotionBlur.OversampleMultiplier)
+
+	adaptive := settings.Recording.MotionBlur.Adaptive
+	if !adaptive.Enabled || adaptive.SpeedThreshold <= 0 {
+		return maxCount
+	}
+
+	minCount := bmath.ClampI(adaptive.MinMultiplier, 1, maxCount)
+
+	progress := bmath.ClampF64(cursorSpeed/adaptive.SpeedThreshold, 0, 1)
+
+	return minCount + int(math.Round(progress*float64(maxCount-minCount)))
+}
+
+// blendCount scales the amount of blended frames to the number of subframes that were actually rendered
+func blendCount(subFrames int) int {
+	blur := settings.Recording.MotionBlur
+
+	count := int(math.Ceil(float64(blur.BlendFrames) * float64(subFrames) / float64(bmath.MaxI(1, blur.OversampleMultiplier))))
+
+	return bmath.ClampI(count, 1, blur.BlendFrames)
+}
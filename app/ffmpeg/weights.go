@@ -56,6 +56,10 @@ func calculateWeights(bFrames int) []float32 {
 
 			weights = append(weights, float32(v))
 		}
+
+		if len(weights) != bFrames {
+			weights = resampleWeights(weights, bFrames)
+		}
 	} else {
 		id := settings.Recording.MotionBlur.BlendWeights.AutoWeightsID
 		if id < 0 || id > len(easings) {
@@ -64,7 +68,12 @@ func calculateWeights(bFrames int) []float32 {
 
 		easeFunc := easings[id]
 		for i := 0; i < bFrames; i++ {
-			w := 1.0 + easeFunc(float64(i)/float64(bFrames-1))*100
+			t := 1.0
+			if bFrames > 1 {
+				t = float64(i) / float64(bFrames-1)
+			}
+
+			w := 1.0 + easeFunc(t)*100
 			weights = append(weights, float32(w))
 		}
 	}
@@ -72,6 +81,30 @@ func calculateWeights(bFrames int) []float32 {
 	return weights
 }
 
+// resampleWeights linearly stretches or shrinks manual weights, needed when adaptive motion blur renders fewer subframes
+func resampleWeights(weights []float32, count int) []float32 {
+	if len(weights) == 1 || count == 1 {
+		return []float32{weights[len(weights)-1]}
+	}
+
+	result := make([]float32, count)
+
+	for i := range result {
+		pos := float64(i) / float64(count-1) * float64(len(weights)-1)
+
+		index := int(pos)
+		if index >= len(weights)-1 {
+			result[i] = weights[len(weights)-1]
+			continue
+		}
+
+		t := float32(pos - float64(index))
+		result[i] = weights[index]*(1-t) + weights[index+1]*t
+	}
+
+	return result
+}
+
 func flat(_ float64) float64 {
 	return 1.0
 }
@@ -99,6 +99,13 @@ type Cursor struct {
 
 	rippleContainer *sprite.SpriteManager
 	time            float64
+
+	history []positionSample
+}
+
+type positionSample struct {
+	time     float64
+	position vector.Vector2f
 }
 
 func NewCursor() *Cursor {
@@ -209,6 +216,49 @@ func (cursor *Cursor) Update(delta float64) {
 	cursor.renderer.Update(delta, cursor.Position)
 
 	cursor.rippleContainer.Update(cursor.time)
+
+	cursor.updateHistory()
+}
+
+// updateHistory stores positions needed by delayed symmetry copies
+func (cursor *Cursor) updateHistory() {
+	maxDelay := settings.Playfield.Symmetry.GetMaxDelay()
+	if maxDelay <= 0 {
+		cursor.history = cursor.history[:0]
+		return
+	}
+
+	cursor.history = append(cursor.history, positionSample{cursor.time, cursor.Position})
+
+	toRemove := 0
+	for toRemove < len(cursor.history)-1 && cursor.history[toRemove+1].time < cursor.time-maxDelay {
+		toRemove++
+	}
+
+	cursor.history = cursor.history[toRemove:]
+}
+
+// GetDelayedPosition returns cursor position from given ms ago, limited by the longest symmetry copy delay
+func (cursor *Cursor) GetDelayedPosition(delay float64) vector.Vector2f {
+	if delay <= 0 || len(cursor.history) == 0 {
+		return cursor.Position
+	}
+
+	target := cursor.time - delay
+
+	for i := len(cursor.history) - 1; i > 0; i-- {
+		if prev := cursor.history[i-1]; prev.time <= target {
+			next := cursor.history[i]
+
+			if next.time == prev.time {
+				return next.position
+			}
+
+			return prev.position.Lerp(next.position, float32((target-prev.time)/(next.time-prev.time)))
+		}
+	}
+
+	return cursor.history[0].position
 }
 
 func (cursor *Cursor) smokeUpdate() {
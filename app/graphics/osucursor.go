@@ -64,6 +64,7 @@ type osuRenderer struct {
 	trail  *texture.TextureRegion
 	cursor *sprite.Sprite
 	middle *sprite.Sprite
+	info   *skin.SkinInfo
 
 	clock       float64
 	manager     *sprite.SpriteManager
@@ -116,8 +117,10 @@ func newOsuRenderer() *osuRenderer {
 
 	cursorTexture := skin.GetTexture("cursor")
 
+	cursor.info = skin.GetInfoFor("cursor")
+
 	origin := bmath.Origin.Centre
-	if !skin.GetInfo().CursorCentre {
+	if !cursor.info.CursorCentre {
 		origin = bmath.Origin.TopLeft
 	}
 
@@ -253,7 +256,7 @@ func (cursor *osuRenderer) UpdateRenderer() {
 
 func (cursor *osuRenderer) DrawM(scale, expand float64, batch *batch.QuadBatch, color color2.Color, colorGlow color2.Color) {
 	scale *= settings.Skin.Cursor.Scale
-	if skin.GetInfo().CursorExpand {
+	if cursor.info.CursorExpand {
 		scale *= expand
 	}
 
@@ -301,7 +304,7 @@ func (cursor *osuRenderer) DrawM(scale, expand float64, batch *batch.QuadBatch,
 
 	batch.SetTranslation(position.Copy64())
 
-	if skin.GetInfo().CursorRotate {
+	if cursor.info.CursorRotate {
 		cursor.cursor.SetRotation(cursor.clock / 10 / 10 * 2 * math.Pi)
 	} else {
 		cursor.cursor.SetRotation(0)
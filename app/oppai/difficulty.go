@@ -28,6 +28,9 @@ type Stars struct {
 
 	// Speed stars, needed for Performance Points (aka PP) calculations
 	Speed float64
+
+	// Aim and speed strain right after the object, filled only by CalculateStep
+	AimStrain, SpeedStrain float64
 }
 
 // Retrieves skills values and converts to Stars
@@ -86,7 +89,11 @@ func CalculateStep(objects []objects.IHitObject, diff *difficulty.Difficulty, us
 		aimSkill.Process(o)
 		speedSkill.Process(o)
 
-		stars = append(stars, getStars(aimSkill, speedSkill, diff))
+		star := getStars(aimSkill, speedSkill, diff)
+		star.AimStrain = aimSkill.CurrentStrain
+		star.SpeedStrain = speedSkill.CurrentStrain
+
+		stars = append(stars, star)
 
 		if len(diffObjects) > 2500 {
 			progress := (100 * i) / (len(diffObjects) - 1)
@@ -599,6 +599,66 @@ func (set *OsuRuleSet) GetPP(cursor *graphics.Cursor) float64 {
 	return subSet.ppv2.Total
 }
 
+// GetStepStars returns star ratings after every object for mods used by the cursor, first entry is the one before any object
+func (set *OsuRuleSet) GetStepStars(cursor *graphics.Cursor) []oppai.Stars {
+	subSet := set.cursors[cursor]
+	return set.oppDiffs[subSet.player.diff.Mods&difficulty.DifficultyAdjustMask]
+}
+
+// GetRemaining returns the number of objects that haven't been judged yet
+func (set *OsuRuleSet) GetRemaining(cursor *graphics.Cursor) int64 {
+	subSet := set.cursors[cursor]
+	return int64(len(set.beatMap.HitObjects)) - subSet.numObjects
+}
+
+// GetMaxPP returns pp the cursor would get if all remaining objects were hit perfectly
+func (set *OsuRuleSet) GetMaxPP(cursor *graphics.Cursor) float64 {
+	subSet := set.cursors[cursor]
+
+	remainingCombo := set.mapStats[len(set.mapStats)-1].maxCombo
+	if subSet.numObjects > 0 {
+		remainingCombo -= set.mapStats[subSet.numObjects-1].maxCombo
+	}
+
+	maxCombo := bmath.MaxI(int(subSet.maxCombo), int(subSet.combo)+remainingCombo)
+	remaining := int(set.GetRemaining(cursor))
+
+	return set.calculateFullPP(subSet, maxCombo, int(subSet.hits[Hit300])+remaining, int(subSet.hits[Hit100]), int(subSet.hits[Hit50]), int(subSet.hits[Miss]))
+}
+
+// GetFCPP returns pp the cursor would get if misses and remaining objects were 300s and combo was never broken
+func (set *OsuRuleSet) GetFCPP(cursor *graphics.Cursor) float64 {
+	subSet := set.cursors[cursor]
+
+	mapTo := set.mapStats[len(set.mapStats)-1]
+
+	n300 := mapTo.nobjects - int(subSet.hits[Hit100]) - int(subSet.hits[Hit50])
+
+	return set.calculateFullPP(subSet, mapTo.maxCombo, n300, int(subSet.hits[Hit100]), int(subSet.hits[Hit50]), 0)
+}
+
+// GetSSPP returns pp for a perfect play with cursor's mods
+func (set *OsuRuleSet) GetSSPP(cursor *graphics.Cursor) float64 {
+	subSet := set.cursors[cursor]
+
+	mapTo := set.mapStats[len(set.mapStats)-1]
+
+	return set.calculateFullPP(subSet, mapTo.maxCombo, mapTo.nobjects, 0, 0, 0)
+}
+
+// calculateFullPP calculates pp for the whole map with given hits, it doesn't touch the live pp of the cursor
+func (set *OsuRuleSet) calculateFullPP(subSet *subSet, combo, n300, n100, n50, nmiss int) float64 {
+	stars := set.oppDiffs[subSet.player.diff.Mods&difficulty.DifficultyAdjustMask]
+	diff := stars[len(stars)-1]
+
+	mapTo := set.mapStats[len(set.mapStats)-1]
+
+	ppv2 := new(oppai.PPv2)
+	ppv2.PPv2x(diff.Aim, diff.Speed, mapTo.maxCombo, mapTo.nsliders, mapTo.ncircles, mapTo.nobjects, combo, n300, n100, n50, nmiss, subSet.player.diff, 1)
+
+	return ppv2.Total
+}
+
 func (set *OsuRuleSet) IsPerfect(cursor *graphics.Cursor) bool {
 	subSet := set.cursors[cursor]
 	return subSet.maxCombo == int64(set.mapStats[subSet.numObjects-1].maxCombo)
@@ -13,6 +13,14 @@ func initAudio() *audio {
 		IgnoreBeatmapSampleVolume:  false,
 		BeatScale:                  1.2,
 		BeatUseTimingPoints:        false,
+		Mixer: &mixer{
+			Master:      newBus(),
+			Music:       newBus(),
+			Hitsounds:   newBus(),
+			SliderLoops: newBus(),
+			Storyboard:  newBus(),
+			UI:          newBus(),
+		},
 		NonWindows: &nonWindows{
 			BassPlaybackBufferLength: 100,
 			BassDeviceBufferLength:   10,
@@ -31,9 +39,60 @@ type audio struct {
 	IgnoreBeatmapSampleVolume  bool //= false
 	BeatScale                  float64
 	BeatUseTimingPoints        bool
+	Mixer                      *mixer
 	NonWindows                 *nonWindows `json:"Linux/Unix"`
 }
 
+type mixer struct {
+	Master      *audioBus
+	Music       *audioBus
+	Hitsounds   *audioBus
+	SliderLoops *audioBus
+	Storyboard  *audioBus
+	UI          *audioBus
+}
+
+// GetBus returns the settings of a bus by its name, unknown names fall back to Master
+func (m *mixer) GetBus(name string) *audioBus {
+	switch name {
+	case "Music":
+		return m.Music
+	case "Hitsounds":
+		return m.Hitsounds
+	case "SliderLoops":
+		return m.SliderLoops
+	case "Storyboard":
+		return m.Storyboard
+	case "UI":
+		return m.UI
+	}
+
+	return m.Master
+}
+
+func newBus() *audioBus {
+	return &audioBus{
+		Gain:           1,
+		Limiter:        false,
+		LimiterCeiling: -1,
+		LoudnessTarget: 0,
+	}
+}
+
+type audioBus struct {
+	// Linear volume multiplier of the bus
+	Gain float64
+
+	// Whether peaks above LimiterCeiling should be reduced, recording only
+	Limiter bool
+
+	// Maximum sample peak in dBFS
+	LimiterCeiling float64
+
+	// Integrated loudness (EBU R128) in LUFS the bus will be normalised to, 0 disables normalisation, recording only
+	LoudnessTarget float64
+}
+
 type nonWindows struct {
 	BassPlaybackBufferLength int64
 	BassDeviceBufferLength   int64
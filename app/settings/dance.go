@@ -1,10 +1,17 @@
 package settings
 
+import (
+	"hash/fnv"
+	"time"
+)
+
 var Dance *dance = initDance()
 
 func initDance() *dance {
 	return &dance{
 		Movers:             []string{"spline"},
+		MoverSections:      []*moverSection{},
+		Seed:               0,
 		Spinners:           []string{"circle"},
 		DoSpinnersTogether: true,
 		SpinnerRadius:      100,
@@ -49,24 +56,94 @@ func initDance() *dance {
 		ExGon: &exgon{
 			Delay: 50,
 		},
+		SliderStrategy: &sliderStrategy{
+			Type:         "follow",
+			RadiusFactor: 0.7,
+			Frequency:    2,
+			RampTime:     100,
+		},
+		CustomSpinner: &customSpinner{
+			SVGPath:  "",
+			X:        "cos(t)",
+			Y:        "sin(t)",
+			Speed:    1,
+			Rotate3D: false,
+		},
+		Humanized: &humanized{
+			UnstableRate:    90,
+			Accuracy:        97,
+			FittsA:          40,
+			FittsB:          70,
+			Overshoot:       0.08,
+			Curvature:       0.05,
+			StreamThreshold: 125,
+			HoldTime:        70,
+		},
+		BattleCursors: []*battleCursor{},
+		CursorPath: &cursorPath{
+			Export:     "",
+			SampleRate: 120,
+			Import:     "",
+		},
 	}
 }
 
 type dance struct {
 	Movers             []string
+	MoverSections      []*moverSection
+	Seed               int64 // 0 derives the seed from the map, negative values pick a random one
 	Spinners           []string
 	DoSpinnersTogether bool
 	SpinnerRadius      float64
+	CustomSpinner      *customSpinner
 	Battle             bool
 	SliderDance        bool
 	RandomSliderDance  bool
 	TAGSliderDance     bool
+	SliderStrategy     *sliderStrategy
 	Bezier             *bezier
 	Flower             *flower
 	HalfCircle         *circular
 	Spline             *spline
 	Momentum           *momentum
 	ExGon              *exgon
+	Humanized          *humanized
+	CursorPath         *cursorPath
+	BattleCursors      []*battleCursor
+}
+
+// GetBattleCursor returns profile of index-th battle cursor, nil if there's none
+func (d *dance) GetBattleCursor(index int) *battleCursor {
+	if index < 0 || index >= len(d.BattleCursors) {
+		return nil
+	}
+
+	return d.BattleCursors[index]
+}
+
+// GetSeed returns Seed if it's set, a seed derived from map's MD5 if it's 0 and a time based one if it's negative
+func (d *dance) GetSeed(md5 string) int64 {
+	if d.Seed > 0 {
+		return d.Seed
+	}
+
+	if d.Seed < 0 {
+		return time.Now().UnixNano()
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(md5))
+
+	return int64(hash.Sum64())
+}
+
+// moverSection overrides Movers in parts of the map, first matching section wins
+type moverSection struct {
+	Movers    []string
+	Kiai      bool    // matches kiai sections
+	Parts     []int   // matches break-separated parts of the map, counted from 1
+	StartTime float64 // matches time range in ms if EndTime is bigger than StartTime
+	EndTime   float64
 }
 
 type bezier struct {
@@ -110,3 +187,46 @@ type momentum struct {
 type exgon struct {
 	Delay int64
 }
+
+type humanized struct {
+	UnstableRate    float64 // tap timing jitter, 10 * standard deviation in ms
+	Accuracy        float64 // target accuracy in %, random misses are added if timing jitter alone would give higher accuracy
+	FittsA          float64 // Fitts' law movement time: FittsA + FittsB * log2(distance / circle diameter + 1) in ms
+	FittsB          float64
+	Overshoot       float64 // how far past the target cursor goes before correcting, fraction of jump distance
+	Curvature       float64 // sideways bend of movement, fraction of jump distance
+	StreamThreshold float64 // taps closer than this in ms are alternated between keys
+	HoldTime        float64 // how long keys are held on circles in ms
+}
+
+type cursorPath struct {
+	Export     string  // file (.csv or .json) cursor trajectories will be saved to after the map ends, empty disables export
+	SampleRate float64 // exported samples per second
+	Import     string  // file (.csv or .json) replayed by "path" mover
+}
+
+// battleCursor configures a single cursor in battle mode, empty values fall back to Movers, Spinners and Humanized settings
+type battleCursor struct {
+	Name         string
+	Mover        string
+	Spinner      string
+	UnstableRate float64 // used by humanized mover
+	Accuracy     float64 // used by humanized mover
+}
+
+// customSpinner defines the path of "custom" spinner mover, shape is scaled to SpinnerRadius
+type customSpinner struct {
+	SVGPath  string  // SVG path data ("d" attribute), overrides X and Y
+	X        string  // x(t) formula, t goes from 0 to 2*pi
+	Y        string  // y(t) formula, positive values go down
+	Speed    float64 // how many times the path is traversed per spinner rotation
+	Rotate3D bool    // wobble the shape in 3D like cube spinner mover
+}
+
+// sliderStrategy moves the cursor around the slider ball inside the follow circle, used on sliders that are not danced as circles
+type sliderStrategy struct {
+	Type         string  // follow, orbit, zigzag, figure8 or random (picked per slider)
+	RadiusFactor float64 // fraction of follow circle radius, limited to 0.95 so sliders are still scored
+	Frequency    float64 // pattern repetitions per second
+	RampTime     float64 // ms it takes to move away from the ball after slider start and come back before slider end
+}
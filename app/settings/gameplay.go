@@ -45,10 +45,17 @@ func initGameplay() *gameplay {
 			Align:         "CentreLeft",
 			ShowInResults: true,
 		},
-		KeyOverlay: &hudElement{
-			Show:    true,
-			Scale:   1.0,
-			Opacity: 1.0,
+		KeyOverlay: &keyOverlay{
+			hudElement: &hudElement{
+				Show:    true,
+				Scale:   1.0,
+				Opacity: 1.0,
+			},
+			Style:         "Classic",
+			BarSpeed:      300,
+			BarLength:     320,
+			ShowKPS:       true,
+			ShowDurations: true,
 		},
 		ScoreBoard: &scoreBoard{
 			hudElement: &hudElement{
@@ -69,6 +76,53 @@ func initGameplay() *gameplay {
 			HideInReplays: false,
 			FoldInReplays: false,
 		},
+		StrainGraph: &strainGraph{
+			hudWidget: &hudWidget{
+				hudElement: &hudElement{
+					Show:    false,
+					Scale:   1.0,
+					Opacity: 1.0,
+				},
+				XPosition: 5,
+				YPosition: 690,
+				Align:     "BottomLeft",
+			},
+			Width:  300,
+			Height: 60,
+		},
+		StarRating: &hudWidget{
+			hudElement: &hudElement{
+				Show:    false,
+				Scale:   1.0,
+				Opacity: 1.0,
+			},
+			XPosition: 5,
+			YPosition: 190,
+			Align:     "CentreLeft",
+		},
+		BPMDisplay: &hudWidget{
+			hudElement: &hudElement{
+				Show:    false,
+				Scale:   1.0,
+				Opacity: 1.0,
+			},
+			XPosition: 5,
+			YPosition: 250,
+			Align:     "CentreLeft",
+		},
+		RemainingCounter: &remainingCounter{
+			hudWidget: &hudWidget{
+				hudElement: &hudElement{
+					Show:    false,
+					Scale:   1.0,
+					Opacity: 1.0,
+				},
+				XPosition: 5,
+				YPosition: 280,
+				Align:     "CentreLeft",
+			},
+			Mode: "Objects",
+		},
 		Boundaries: &boundaries{
 			Enabled:         true,
 			BorderThickness: 1,
@@ -88,7 +142,19 @@ func initGameplay() *gameplay {
 		},
 		ShowResultsScreen: true,
 		ResultsScreenTime: 5,
+		ExtendedResults: &extendedResults{
+			Enabled:       false,
+			ShowAfter:     2.5,
+			HitErrors:     true,
+			Accuracy:      true,
+			Combo:         true,
+			PP:            true,
+			Sections:      true,
+			SectionCount:  8,
+			SectionLength: 0,
+		},
 		ShowWarningArrows: true,
+		ShowComboBursts:   true,
 		FlashlightDim:     1,
 		PlayUsername:      "Guest",
 	}
@@ -100,15 +166,24 @@ type gameplay struct {
 	HpBar             *hudElement
 	ComboCounter      *hudElement
 	PPCounter         *ppCounter
-	KeyOverlay        *hudElement
+	KeyOverlay        *keyOverlay
 	ScoreBoard        *scoreBoard
 	Mods              *mods
+	StrainGraph       *strainGraph
+	StarRating        *hudWidget
+	BPMDisplay        *hudWidget
+	RemainingCounter  *remainingCounter
 	Boundaries        *boundaries
 	ShowResultsScreen bool
 	ResultsScreenTime float64
+	ExtendedResults   *extendedResults
 	ShowWarningArrows bool
+	ShowComboBursts   bool
 	FlashlightDim     float64
 	PlayUsername      string
+
+	// Path to the HUD layout file, empty keeps built-in placement. The file is created with defaults if it doesn't exist
+	HUDLayout string
 }
 
 type boundaries struct {
@@ -124,6 +199,21 @@ type boundaries struct {
 	BackgroundOpacity float64
 }
 
+// extendedResults is a statistics page shown over the results screen
+type extendedResults struct {
+	Enabled   bool
+	ShowAfter float64 // Seconds after results screen appears
+
+	HitErrors bool // Hit error histogram
+	Accuracy  bool // Accuracy over time
+	Combo     bool // Combo timeline with breaks and misses
+	PP        bool // pp if FC and SS
+	Sections  bool // Difficulty vs accuracy of each section
+
+	SectionCount  int
+	SectionLength float64 // Length of a section in seconds, overrides SectionCount if above 0
+}
+
 type hudElement struct {
 	Show    bool
 	Scale   float64
@@ -152,6 +242,34 @@ type ppCounter struct {
 	ShowInResults bool
 }
 
+// hudWidget is an optional element placed freely on the screen, like PP counter
+type hudWidget struct {
+	*hudElement
+	XPosition float64
+	YPosition float64
+	Align     string
+}
+
+type strainGraph struct {
+	*hudWidget
+	Width  float64
+	Height float64
+}
+
+type remainingCounter struct {
+	*hudWidget
+	Mode string // "Objects" shows objects left, "MaxPP" shows pp achievable by hitting them perfectly
+}
+
+type keyOverlay struct {
+	*hudElement
+	Style         string  // "Classic" shows stable key boxes, "Bars" shows scrolling press history which is also drawn in cursordance
+	BarSpeed      float64 // Scroll speed of press bars in pixels per second
+	BarLength     float64 // Length of the history in pixels
+	ShowKPS       bool    // Current and max keys per second
+	ShowDurations bool    // Average press duration of each key
+}
+
 type scoreBoard struct {
 	*hudElement
 	HideOthers  bool
@@ -17,11 +17,25 @@ func initGeneral() *general {
 		osuBaseDir = filepath.Join(dir, ".osu")
 	}
 
+	var fallbackFonts []string
+
+	switch runtime.GOOS {
+	case "windows":
+		fontDir := filepath.Join(os.Getenv("windir"), "Fonts")
+		fallbackFonts = []string{filepath.Join(fontDir, "msgothic.ttc"), filepath.Join(fontDir, "malgun.ttf"), filepath.Join(fontDir, "msyh.ttc")}
+	case "darwin":
+		fallbackFonts = []string{"/System/Library/Fonts/ヒラギノ角ゴシック W3.ttc", "/System/Library/Fonts/AppleSDGothicNeo.ttc", "/System/Library/Fonts/PingFang.ttc"}
+	default:
+		fallbackFonts = []string{"/usr/share/fonts/noto-cjk/NotoSansCJK-Regular.ttc", "/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc", "/usr/share/fonts/google-noto-cjk/NotoSansCJK-Regular.ttc"}
+	}
+
 	return &general{
 		OsuSongsDir:       filepath.Join(osuBaseDir, "Songs"),
 		OsuSkinsDir:       filepath.Join(osuBaseDir, "Skins"),
 		DiscordPresenceOn: true,
 		UnpackOszFiles:    true,
+		UnicodeMetadata:   false,
+		FallbackFonts:     fallbackFonts,
 	}
 }
 
@@ -36,6 +50,12 @@ type general struct {
 	// Whether discord should show that danser is on
 	DiscordPresenceOn bool
 
-	// Whether danser should unpack .osz files in Songs folder, osu! may complain about it
+	// Whether danser should unpack .osz files in Songs folder, osu! may complain about it. If disabled, they are read in place
 	UnpackOszFiles bool
+
+	// Whether artist and title should be shown in their original language (if the map provides it) in window title, discord status and overlays
+	UnicodeMetadata bool
+
+	// TTF, OTF or TTC fonts used in order for characters missing in danser's fonts, like CJK player names. Missing files are skipped
+	FallbackFonts []string
 }
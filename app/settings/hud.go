@@ -0,0 +1,129 @@
+package settings
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+)
+
+// HUDLayout places HUD components, it's loaded from a separate file pointed by Gameplay.HUDLayout
+var HUDLayout = initHUDLayout()
+
+func initHUDLayout() *hudLayout {
+	return &hudLayout{
+		Score:      newHUDComponent(),
+		Accuracy:   newHUDComponent(),
+		Combo:      newHUDComponent(),
+		PP:         newHUDComponent(),
+		HitError:   newHUDComponent(),
+		HpBar:      newHUDComponent(),
+		KeyOverlay: newHUDComponent(),
+		Mods:       newHUDComponent(),
+		ScoreBoard: newHUDComponent(),
+		Progress:   newHUDComponent(),
+
+		StrainGraph:      newHUDComponent(),
+		StarRating:       newHUDComponent(),
+		BPMDisplay:       newHUDComponent(),
+		RemainingCounter: newHUDComponent(),
+	}
+}
+
+func newHUDComponent() *HUDComponent {
+	return &HUDComponent{
+		Scale:   1.0,
+		Opacity: 1.0,
+		ShowIn:  []string{},
+	}
+}
+
+type hudLayout struct {
+	Score      *HUDComponent
+	Accuracy   *HUDComponent
+	Combo      *HUDComponent
+	PP         *HUDComponent
+	HitError   *HUDComponent
+	HpBar      *HUDComponent
+	KeyOverlay *HUDComponent
+	Mods       *HUDComponent
+	ScoreBoard *HUDComponent
+	Progress   *HUDComponent
+
+	StrainGraph      *HUDComponent
+	StarRating       *HUDComponent
+	BPMDisplay       *HUDComponent
+	RemainingCounter *HUDComponent
+}
+
+// HUDComponent places a single HUD component on top of its default position.
+// Anchor attaches the matching corner of the component to the same corner of the screen (TopLeft, Centre, BottomRight etc.),
+// empty Anchor keeps the default placement. Scale and Rotation (in degrees) are applied around the anchor.
+// ShowIn limits visibility to given states: Playing, Break, Kiai, Results. Empty list shows the component always.
+type HUDComponent struct {
+	Anchor   string
+	XOffset  float64
+	YOffset  float64
+	Scale    float64
+	Rotation float64
+	Opacity  float64
+	ShowIn   []string
+}
+
+// GetComponents returns components by their names in the layout file
+func (layout *hudLayout) GetComponents() map[string]*HUDComponent {
+	return map[string]*HUDComponent{
+		"Score":      layout.Score,
+		"Accuracy":   layout.Accuracy,
+		"Combo":      layout.Combo,
+		"PP":         layout.PP,
+		"HitError":   layout.HitError,
+		"HpBar":      layout.HpBar,
+		"KeyOverlay": layout.KeyOverlay,
+		"Mods":       layout.Mods,
+		"ScoreBoard": layout.ScoreBoard,
+		"Progress":   layout.Progress,
+
+		"StrainGraph":      layout.StrainGraph,
+		"StarRating":       layout.StarRating,
+		"BPMDisplay":       layout.BPMDisplay,
+		"RemainingCounter": layout.RemainingCounter,
+	}
+}
+
+// loadHUDLayout reads the layout file, a missing file is created with default values to serve as a template.
+// Broken files are reported and ignored so the HUD doesn't disappear mid-edit.
+func loadHUDLayout() {
+	if Gameplay.HUDLayout == "" {
+		HUDLayout = initHUDLayout()
+		return
+	}
+
+	file, err := os.Open(Gameplay.HUDLayout)
+	if os.IsNotExist(err) {
+		HUDLayout = initHUDLayout()
+		saveSettings(Gameplay.HUDLayout, HUDLayout)
+
+		return
+	} else if err != nil {
+		log.Println("SettingsManager: Failed to open HUD layout:", err)
+		return
+	}
+
+	defer file.Close()
+
+	layout := initHUDLayout()
+
+	if err = json.NewDecoder(file).Decode(layout); err != nil {
+		log.Println("SettingsManager: Failed to parse", Gameplay.HUDLayout, "keeping the previous layout. Error:", err)
+		return
+	}
+
+	for _, c := range layout.GetComponents() {
+		if c == nil {
+			log.Println("SettingsManager: HUD layout has empty components, keeping the previous layout")
+			return
+		}
+	}
+
+	HUDLayout = layout
+}
@@ -13,6 +13,7 @@ import (
 var fileStorage *fileformat
 var fileName string
 var watcher *fsnotify.Watcher
+var watchedLayout string
 
 func initStorage() {
 	fileStorage = &fileformat{
@@ -54,6 +55,8 @@ func LoadSettings(version string) bool {
 		saveSettings(fileName, fileStorage) //this is done to save additions from the current format
 	}
 
+	loadHUDLayout()
+
 	if !RECORD {
 		setupWatcher(fileName)
 	}
@@ -78,6 +81,16 @@ func setupWatcher(file string) {
 				}
 
 				if event.Op&fsnotify.Write == fsnotify.Write {
+					if watchedLayout != "" && filepath.Clean(event.Name) == watchedLayout {
+						log.Println("SettingsManager: Detected", Gameplay.HUDLayout, "modification, reloading...")
+
+						time.Sleep(time.Millisecond * 200)
+
+						loadHUDLayout()
+
+						continue
+					}
+
 					log.Println("SettingsManager: Detected", file, "modification, reloading...")
 
 					time.Sleep(time.Millisecond * 200)
@@ -87,6 +100,9 @@ func setupWatcher(file string) {
 					load(sFile, fileStorage)
 
 					sFile.Close()
+
+					loadHUDLayout()
+					watchHUDLayout()
 				}
 			case err, ok := <-watcher.Errors:
 				if !ok {
@@ -104,6 +120,35 @@ func setupWatcher(file string) {
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	watchHUDLayout()
+}
+
+// watchHUDLayout follows changes of Gameplay.HUDLayout path
+func watchHUDLayout() {
+	abs := ""
+	if Gameplay.HUDLayout != "" {
+		abs, _ = filepath.Abs(Gameplay.HUDLayout)
+	}
+
+	if abs == watchedLayout {
+		return
+	}
+
+	if watchedLayout != "" {
+		_ = watcher.Remove(watchedLayout)
+	}
+
+	watchedLayout = ""
+
+	if abs != "" {
+		if err := watcher.Add(abs); err != nil {
+			log.Println("SettingsManager: Failed to watch HUD layout:", err)
+			return
+		}
+
+		watchedLayout = abs
+	}
 }
 
 func CloseWatcher() {
@@ -1,5 +1,11 @@
 package settings
 
+import (
+	"encoding/json"
+	color2 "github.com/tsunyoku/danser/framework/math/color"
+	"math"
+)
+
 var Playfield = initPlayfield()
 
 func initPlayfield() *playfield {
@@ -65,6 +71,11 @@ func initPlayfield() *playfield {
 			Blur:              0.6,
 			Power:             0.7,
 		},
+		Symmetry: &symmetry{
+			Enabled:      false,
+			Kaleidoscope: false,
+			Copies:       []*symmetryCopy{},
+		},
 	}
 }
 
@@ -83,6 +94,7 @@ type playfield struct {
 	Background                   *background
 	Logo                         *logo
 	Bloom                        *bloom
+	Symmetry                     *symmetry
 }
 
 type seizure struct {
@@ -162,3 +174,87 @@ type bloom struct {
 	Blur              float64
 	Power             float64
 }
+
+// Symmetry controls how -cursors copies of the playfield are transformed
+type symmetry struct {
+	Enabled bool
+
+	// Every other copy is mirrored, making the pattern symmetric across axes between copies
+	Kaleidoscope bool
+
+	// Per-copy settings, they are repeated if there are more copies than entries
+	Copies []*symmetryCopy
+}
+
+type symmetryCopy struct {
+	Scale       float64 //1
+	OffsetX     float64 //offset in osu!pixels, applied before rotation
+	OffsetY     float64
+	Rotation    float64 //additional rotation in degrees
+	MirrorX     bool    //reflect the copy horizontally
+	MirrorY     bool    //reflect the copy vertically
+	CursorDelay float64 //cursors of this copy lag behind by given ms
+	HueOffset   float64 //added to object and cursor hues of this copy
+	Alpha       float64 //1
+}
+
+var identityCopy = &symmetryCopy{Scale: 1, Alpha: 1}
+
+// UnmarshalJSON starts from identity so keys missing in user-written copies don't make them invisible
+func (c *symmetryCopy) UnmarshalJSON(data []byte) error {
+	type plainCopy symmetryCopy
+
+	decoded := plainCopy(*identityCopy)
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*c = symmetryCopy(decoded)
+
+	return nil
+}
+
+// GetCopy returns settings of i-th copy, identity if symmetry is disabled
+func (sm *symmetry) GetCopy(i int) *symmetryCopy {
+	if !sm.Enabled || len(sm.Copies) == 0 {
+		return identityCopy
+	}
+
+	return sm.Copies[i%len(sm.Copies)]
+}
+
+// GetMaxDelay returns the longest cursor delay of all copies
+func (sm *symmetry) GetMaxDelay() float64 {
+	maxDelay := 0.0
+
+	if sm.Enabled {
+		for _, c := range sm.Copies {
+			maxDelay = math.Max(maxDelay, c.CursorDelay)
+		}
+	}
+
+	return maxDelay
+}
+
+// AdjustColors applies hue offsets and alpha of copies, colors are grouped by copy with perCopy colors each
+func (sm *symmetry) AdjustColors(colors []color2.Color, perCopy int) []color2.Color {
+	if !sm.Enabled || len(sm.Copies) == 0 {
+		return colors
+	}
+
+	adjusted := make([]color2.Color, len(colors))
+
+	for i, c := range colors {
+		sCopy := sm.GetCopy(i / perCopy)
+
+		if sCopy.HueOffset != 0 {
+			c = c.Shift(float32(sCopy.HueOffset), 0, 0)
+		}
+
+		c.A *= float32(sCopy.Alpha)
+
+		adjusted[i] = c
+	}
+
+	return adjusted
+}
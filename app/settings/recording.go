@@ -16,6 +16,7 @@ func initRecording() *recording {
 		AudioCodec:     "aac",
 		AudioBitrate:   "320k",
 		AudioFilters:   "",
+		ExportStems:    false,
 		OutputDir:      "videos",
 		Container:      "mp4",
 		MotionBlur: &motionblur{
@@ -28,6 +29,19 @@ func initRecording() *recording {
 				AutoWeightsID:    1,
 				GaussWeightsMult: 1.5,
 			},
+			ShutterAngle: 360,
+			ShutterPhase: 0,
+			SharpHUD:     false,
+			Adaptive: &adaptiveBlur{
+				Enabled:        false,
+				MinMultiplier:  1,
+				SpeedThreshold: 5,
+			},
+		},
+		Interpolation: &interpolation{
+			Enabled:   false,
+			RenderFPS: 30,
+			Mode:      "blend",
 		},
 	}
 }
@@ -45,9 +59,11 @@ type recording struct {
 	AudioCodec     string
 	AudioBitrate   string
 	AudioFilters   string
+	ExportStems    bool
 	OutputDir      string
 	Container      string
 	MotionBlur     *motionblur
+	Interpolation  *interpolation
 }
 
 type motionblur struct {
@@ -55,6 +71,39 @@ type motionblur struct {
 	OversampleMultiplier int
 	BlendFrames          int
 	BlendWeights         *blendWeights
+
+	// Portion of the frame interval (in degrees, 360 is the whole interval) during which subframes are sampled
+	ShutterAngle float64
+
+	// Offset (in degrees) of the moment the shutter opens, positive values move it towards the next frame
+	ShutterPhase float64
+
+	// Whether HUD should be drawn once on top of blended frame, only cursors, objects and backgrounds get blurred.
+	// HUDs drawn under the cursor, like the score overlay, stay in blurred frame so cursor keeps being on top of them
+	SharpHUD bool
+
+	// Scales the number of rendered subframes by cursor speed
+	Adaptive *adaptiveBlur
+}
+
+type adaptiveBlur struct {
+	Enabled bool
+
+	// Lowest OversampleMultiplier used when cursors are still
+	MinMultiplier int
+
+	// Cursor speed (in osu!pixels per millisecond) at which full OversampleMultiplier is used
+	SpeedThreshold float64
+}
+
+type interpolation struct {
+	// Render frames at RenderFPS and let ffmpeg synthesise the missing ones up to FPS
+	Enabled bool
+
+	RenderFPS int
+
+	// ffmpeg's minterpolate mode: "dup", "blend" or "mci"
+	Mode string
 }
 
 type blendWeights struct {
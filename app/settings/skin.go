@@ -7,6 +7,7 @@ func initSkin() *skin {
 		CurrentSkin:       "default",
 		UseColorsFromSkin: false,
 		UseBeatmapColors:  false,
+		Layers:            []*skinLayer{},
 		Cursor: &skinCursor{
 			UseSkinCursor:    false,
 			Scale:            1.0,
@@ -22,9 +23,19 @@ type skin struct {
 	UseColorsFromSkin bool
 	UseBeatmapColors  bool
 
+	// Layers are other skins consulted in order for missing elements, CurrentSkin goes first unless it's listed
+	Layers []*skinLayer
+
 	Cursor *skinCursor
 }
 
+type skinLayer struct {
+	Name string
+
+	// Elements limits the layer to matching texture and sample names like "cursor*" or "hitcircle*", empty means all
+	Elements []string
+}
+
 type skinCursor struct {
 	UseSkinCursor    bool
 	Scale            float64 `max:"2"`
@@ -0,0 +1,410 @@
+package skin
+
+import (
+	"fmt"
+	"github.com/tsunyoku/danser/app/utils"
+	"github.com/tsunyoku/danser/framework/graphics/texture"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	sourceDefault = "default"
+	sourceMissing = "missing"
+)
+
+// ElementCheck describes where a texture was loaded from and what's wrong with it
+type ElementCheck struct {
+	Name     string
+	Source   string
+	Frames   []*texture.TextureRegion
+	Problems []string
+
+	files *utils.FileMap
+	frame string
+}
+
+// SampleCheck describes where a hitsound was loaded from and what's wrong with it
+type SampleCheck struct {
+	Name     string
+	Source   string
+	Problems []string
+}
+
+type CheckReport struct {
+	Skin     string
+	Elements []*ElementCheck
+	Samples  []*SampleCheck
+}
+
+type checkedElement struct {
+	name     string
+	animated bool
+	dash     bool
+}
+
+var checkedElements = []checkedElement{
+	{"hitcircle", false, false},
+	{"hitcircleoverlay", false, false},
+	{"approachcircle", false, false},
+	{"sliderstartcircle", false, false},
+	{"sliderstartcircleoverlay", false, false},
+	{"sliderendcircle", false, false},
+	{"sliderendcircleoverlay", false, false},
+	{"reversearrow", false, false},
+	{"sliderscorepoint", false, false},
+	{"sliderb", true, false},
+	{"sliderfollowcircle", true, true},
+	{"followpoint", true, true},
+	{"spinner-approachcircle", false, false},
+	{"spinner-background", false, false},
+	{"spinner-bottom", false, false},
+	{"spinner-circle", false, false},
+	{"spinner-clear", false, false},
+	{"spinner-glow", false, false},
+	{"spinner-metre", false, false},
+	{"spinner-middle", false, false},
+	{"spinner-middle2", false, false},
+	{"spinner-rpm", false, false},
+	{"spinner-spin", false, false},
+	{"spinner-top", false, false},
+	{"hit0", true, true},
+	{"hit50", true, true},
+	{"hit100", true, true},
+	{"hit100k", true, true},
+	{"hit300", true, true},
+	{"hit300g", true, true},
+	{"hit300k", true, true},
+	{"cursor", false, false},
+	{"cursormiddle", false, false},
+	{"cursortrail", false, false},
+	{"scorebar-bg", false, false},
+	{"scorebar-colour", true, true},
+	{"scorebar-ki", false, false},
+	{"scorebar-kidanger", false, false},
+	{"scorebar-kidanger2", false, false},
+	{"scorebar-marker", false, false},
+	{"section-pass", false, false},
+	{"section-fail", false, false},
+	{"play-skip", true, true},
+	{"play-warningarrow", false, false},
+	{"arrow-warning", false, false},
+	{"comboburst", true, true},
+	{"ready", false, false},
+	{"count3", false, false},
+	{"count2", false, false},
+	{"count1", false, false},
+	{"go", false, false},
+	{"inputoverlay-background", false, false},
+	{"inputoverlay-key", false, false},
+	{"ranking-panel", false, false},
+	{"ranking-title", false, false},
+	{"ranking-accuracy", false, false},
+	{"ranking-maxcombo", false, false},
+	{"ranking-graph", false, false},
+	{"ranking-perfect", false, false},
+}
+
+var checkedRanks = []string{"XH", "X", "SH", "S", "A", "B", "C", "D"}
+
+var checkedSamples = []string{"combobreak", "spinnerspin", "spinnerbonus", "sectionpass", "sectionfail"}
+
+var frameRegex = regexp.MustCompile(`^(\d+)(@2x)?\.(png|jpg|jpeg)$`)
+
+// Check inspects textures and hitsounds of current skin chain. Textures are loaded exactly the way gameplay does,
+// so the report shows what would be actually drawn.
+func Check() *CheckReport {
+	checkInit()
+
+	report := &CheckReport{
+		Skin: CurrentSkin,
+	}
+
+	elements := append([]checkedElement{}, checkedElements...)
+
+	for _, rank := range checkedRanks {
+		elements = append(elements, checkedElement{"ranking-" + rank, false, false}, checkedElement{"ranking-" + rank + "-small", false, false})
+	}
+
+	for _, prefix := range []string{info.HitCirclePrefix, info.ScorePrefix, info.ComboPrefix} {
+		for i := 0; i < 10; i++ {
+			elements = append(elements, checkedElement{prefix + "-" + strconv.Itoa(i), false, false})
+		}
+	}
+
+	for _, suffix := range []string{"comma", "dot", "percent", "x"} {
+		elements = append(elements, checkedElement{info.ScorePrefix + "-" + suffix, false, false})
+	}
+
+	checked := make(map[string]bool)
+
+	for _, e := range elements {
+		if checked[e.name] {
+			continue
+		}
+
+		checked[e.name] = true
+
+		report.Elements = append(report.Elements, checkElement(e))
+	}
+
+	for _, set := range []string{"normal", "soft", "drum"} {
+		for _, hitSound := range []string{"hitnormal", "hitwhistle", "hitfinish", "hitclap", "slidertick", "sliderslide", "sliderwhistle"} {
+			report.Samples = append(report.Samples, checkSample(set+"-"+hitSound))
+		}
+	}
+
+	for _, name := range checkedSamples {
+		report.Samples = append(report.Samples, checkSample(name))
+	}
+
+	return report
+}
+
+// ProblemCount returns the number of problems found in elements and samples
+func (report *CheckReport) ProblemCount() (count int) {
+	for _, e := range report.Elements {
+		count += len(e.Problems)
+	}
+
+	for _, s := range report.Samples {
+		count += len(s.Problems)
+	}
+
+	return
+}
+
+// LoadPixmap reads the first frame of the element from its source, second value reports whether it's an @2x image
+func (element *ElementCheck) LoadPixmap() (*texture.Pixmap, bool, error) {
+	if element.Source == sourceMissing {
+		return nil, false, fmt.Errorf("%s is missing", element.Name)
+	}
+
+	if pixmap, err := getPixmap(element.files, element.frame+"@2x.png"); err == nil {
+		return pixmap, true, nil
+	}
+
+	pixmap, err := getPixmap(element.files, element.frame+".png")
+
+	return pixmap, false, err
+}
+
+func checkElement(e checkedElement) *ElementCheck {
+	element := &ElementCheck{
+		Name:   e.name,
+		Source: sourceMissing,
+		frame:  e.name,
+	}
+
+	dash := ""
+	if e.dash {
+		dash = "-"
+	}
+
+	if e.animated {
+		element.Frames = GetFrames(e.name, e.dash)
+	} else if tex := GetTexture(e.name); tex != nil {
+		element.Frames = []*texture.TextureRegion{tex}
+	}
+
+	layerIndex := -1
+
+	if len(element.Frames) > 0 {
+		source := GetSourceFromTexture(element.Frames[0])
+
+		layerIndex, _ = getLayer(source)
+
+		if layerIndex >= 0 {
+			element.Source = layers[layerIndex].name
+			element.files = layers[layerIndex].files
+		} else {
+			element.Source = sourceDefault
+		}
+
+		if e.animated && element.Frames[0] == GetTextureSource(e.name+dash+"0", source) {
+			element.frame = e.name + dash + "0"
+		}
+	}
+
+	// Broken files are skipped silently by GetTextureSource so every layer consulted before the actual source is suspicious
+	for i, l := range layers {
+		if i == layerIndex {
+			break
+		}
+
+		if !l.provides(e.name) {
+			continue
+		}
+
+		names := []string{e.name}
+		if e.animated {
+			names = append(names, e.name+dash+"0")
+		}
+
+		for _, name := range names {
+			if l.files.Exists(name+".png") || l.files.Exists(name+"@2x.png") {
+				element.Problems = append(element.Problems, fmt.Sprintf("%s.png in \"%s\" can't be loaded, %s is used instead", name, l.name, element.Source))
+				break
+			}
+		}
+	}
+
+	if layerIndex < 0 {
+		return element
+	}
+
+	files := layers[layerIndex].files
+
+	frameNames := []string{element.frame}
+	if element.frame != e.name {
+		frameNames = frameNames[:0]
+		for i := range element.Frames {
+			frameNames = append(frameNames, e.name+dash+strconv.Itoa(i))
+		}
+	}
+
+	for _, name := range frameNames {
+		element.Problems = append(element.Problems, checkDimensions(files, name)...)
+	}
+
+	if e.animated {
+		element.Problems = append(element.Problems, checkFrameGaps(files, e.name+dash, len(frameNames), element.frame != e.name)...)
+	}
+
+	return element
+}
+
+// checkDimensions flags @2x images which can't be halved cleanly or don't match their SD counterpart
+func checkDimensions(files *utils.FileMap, name string) (problems []string) {
+	w2, h2, hd := imageSize(files, name+"@2x.png")
+	w1, h1, sd := imageSize(files, name+".png")
+
+	if hd && (w2%2 != 0 || h2%2 != 0) {
+		problems = append(problems, fmt.Sprintf("%s@2x.png has odd size %dx%d", name, w2, h2))
+	}
+
+	if hd && sd && (absI(w2-2*w1) > 1 || absI(h2-2*h1) > 1) {
+		problems = append(problems, fmt.Sprintf("%s@2x.png is %dx%d but %s.png is %dx%d, expected %dx%d", name, w2, h2, name, w1, h1, w1*2, h1*2))
+	}
+
+	if !hd && files.Exists(name+"@2x.png") {
+		problems = append(problems, fmt.Sprintf("%s@2x.png is broken", name))
+	}
+
+	return
+}
+
+// checkFrameGaps finds animation frames which exist in skin files but are never reached because GetFrames stops at the first missing one
+func checkFrameGaps(files *utils.FileMap, prefix string, loaded int, animated bool) (problems []string) {
+	if !animated {
+		loaded = 0
+	}
+
+	lowerPrefix := strings.ToLower(prefix)
+
+	ignored := make(map[int]bool)
+
+	for _, file := range files.GetFiles() {
+		lower := strings.ToLower(file)
+		if !strings.HasPrefix(lower, lowerPrefix) {
+			continue
+		}
+
+		matches := frameRegex.FindStringSubmatch(strings.TrimPrefix(lower, lowerPrefix))
+		if matches == nil {
+			continue
+		}
+
+		if index, _ := strconv.Atoi(matches[1]); index >= loaded {
+			ignored[index] = true
+		}
+	}
+
+	if len(ignored) == 0 {
+		return
+	}
+
+	indices := make([]int, 0, len(ignored))
+	for i := range ignored {
+		indices = append(indices, i)
+	}
+
+	sort.Ints(indices)
+
+	frames := make([]string, len(indices))
+	for i, index := range indices {
+		frames[i] = strconv.Itoa(index)
+	}
+
+	return append(problems, fmt.Sprintf("frame %s%d is missing, frames %s are ignored", prefix, loaded, strings.Join(frames, ", ")))
+}
+
+func checkSample(name string) *SampleCheck {
+	sample := &SampleCheck{
+		Name:   name,
+		Source: sourceMissing,
+	}
+
+	loaded := GetSample(name)
+
+	layerIndex := -1
+
+	if loaded != nil {
+		if layerIndex, _ = getLayer(sampleSources[loaded]); layerIndex >= 0 {
+			sample.Source = layers[layerIndex].name
+		} else {
+			sample.Source = sourceDefault
+		}
+	} else {
+		sample.Problems = append(sample.Problems, "sample is missing")
+	}
+
+	for i, l := range layers {
+		if i == layerIndex {
+			break
+		}
+
+		if !l.provides(name) {
+			continue
+		}
+
+		for _, ext := range []string{".wav", ".ogg", ".mp3"} {
+			if l.files.Exists(name + ext) {
+				sample.Problems = append(sample.Problems, fmt.Sprintf("%s%s in \"%s\" can't be loaded, %s is used instead", name, ext, l.name, sample.Source))
+				break
+			}
+		}
+	}
+
+	return sample
+}
+
+// imageSize reads only the header of the image
+func imageSize(files *utils.FileMap, name string) (int, int, bool) {
+	file, err := files.Open(name)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	defer file.Close()
+
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return config.Width, config.Height, true
+}
+
+func absI(a int) int {
+	if a < 0 {
+		return -a
+	}
+
+	return a
+}
@@ -33,15 +33,17 @@ type SkinInfo struct {
 
 	LayeredHitSounds bool
 
-	//skipping combo bursts
+	ComboBurstRandom       bool
+	CustomComboBurstSounds []int
 
-	CursorCentre bool
-	CursorExpand bool
-	CursorRotate bool
+	CursorCentre      bool
+	CursorExpand      bool
+	CursorRotate      bool
+	CursorTrailRotate bool
 
 	ComboColors []color.Color
 
-	//slider style unnecessary
+	SliderStyle int
 
 	SliderBallTint      bool
 	SliderBallFlip      bool
@@ -52,6 +54,15 @@ type SkinInfo struct {
 	SongSelectInactiveText color.Color
 	SongSelectActiveText   color.Color
 	InputOverlayText       color.Color
+	MenuGlow               color.Color
+	SpinnerBackground      color.Color
+	SpinnerTextColour      color.Color
+	StarBreakAdditive      color.Color
+
+	//catch the beat colours, after image and fruit colours are nil if not specified
+	HyperDash           color.Color
+	HyperDashFruit      *color.Color
+	HyperDashAfterImage *color.Color
 
 	//hit circle font settings
 	HitCirclePrefix             string
@@ -65,6 +76,9 @@ type SkinInfo struct {
 	//combo font settings
 	ComboPrefix  string
 	ComboOverlap float64
+
+	//mania configurations by key count
+	Mania map[int]*ManiaInfo
 }
 
 func newDefaultInfo() *SkinInfo {
@@ -77,15 +91,18 @@ func newDefaultInfo() *SkinInfo {
 		SpinnerNoBlink:           false,
 		SpinnerFrequencyModulate: true,
 		LayeredHitSounds:         true,
+		ComboBurstRandom:         false,
 		CursorCentre:             true,
 		CursorExpand:             true,
 		CursorRotate:             true,
+		CursorTrailRotate:        true,
 		ComboColors: []color.Color{
 			color.NewIRGB(255, 192, 0),
 			color.NewIRGB(0, 202, 0),
 			color.NewIRGB(18, 124, 255),
 			color.NewIRGB(242, 24, 57),
 		},
+		SliderStyle:                 2,
 		SliderBallTint:              false,
 		SliderBallFlip:              false,
 		SliderBorder:                color.NewL(1),
@@ -93,16 +110,31 @@ func newDefaultInfo() *SkinInfo {
 		SongSelectInactiveText:      color.NewL(1),
 		SongSelectActiveText:        color.NewL(0),
 		InputOverlayText:            color.NewL(1),
+		MenuGlow:                    color.NewIRGB(0, 78, 155),
+		SpinnerBackground:           color.NewIRGB(100, 100, 100),
+		SpinnerTextColour:           color.NewL(1),
+		StarBreakAdditive:           color.NewIRGB(255, 182, 193),
+		HyperDash:                   color.NewIRGB(255, 0, 0),
 		HitCirclePrefix:             "default",
 		HitCircleOverlap:            -2,
-		HitCircleOverlayAboveNumber: false,
+		HitCircleOverlayAboveNumber: true,
 		ScorePrefix:                 "score",
 		ScoreOverlap:                0,
 		ComboPrefix:                 "score",
 		ComboOverlap:                0,
+		Mania:                       make(map[int]*ManiaInfo),
 	}
 }
 
+// GetMania returns mania configuration for given key count, default one is created if skin doesn't specify it
+func (info *SkinInfo) GetMania(keys int) *ManiaInfo {
+	if mania, exists := info.Mania[keys]; exists {
+		return mania
+	}
+
+	return newManiaInfo(keys)
+}
+
 func (info *SkinInfo) GetFrameTime(frames int) float64 {
 	if info.AnimationFramerate > 0 {
 		return 1000.0 / info.AnimationFramerate
@@ -130,6 +162,33 @@ func tokenize(line, delimiter string) []string {
 	return divided
 }
 
+// normalizePrefix converts font prefixes like "Fonts\score" to paths usable by the skin file map
+func normalizePrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "\"")
+
+	return strings.Trim(strings.ReplaceAll(prefix, "\\", "/"), "/")
+}
+
+// fontKey maps [Fonts] key variants accepted by stable, like "scoreprefix" or "Score Overlap", to their canonical names
+func fontKey(key string) string {
+	switch strings.ToLower(strings.ReplaceAll(key, " ", "")) {
+	case "hitcircleprefix":
+		return "HitCirclePrefix"
+	case "hitcircleoverlap":
+		return "HitCircleOverlap"
+	case "scoreprefix":
+		return "ScorePrefix"
+	case "scoreoverlap":
+		return "ScoreOverlap"
+	case "comboprefix":
+		return "ComboPrefix"
+	case "combooverlap":
+		return "ComboOverlap"
+	}
+
+	return key
+}
+
 func ParseFloat(text, errType string) float64 {
 	value, err := strconv.ParseFloat(text, 64)
 	if err != nil {
@@ -179,14 +238,40 @@ func LoadInfo(path string) (*SkinInfo, error) {
 
 	defer file.Close()
 
+	return ParseInfo(file)
+}
+
+// ParseInfo reads skin.ini from any source, like a file inside .osk archive
+func ParseInfo(file io.Reader) (info *SkinInfo, err error) {
+	// ParseFloat and ParseColor panic on malformed values, report them instead of crashing
+	defer func() {
+		if r := recover(); r != nil {
+			info, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+
 	scanner := util.NewScanner(file)
 
-	info := newDefaultInfo()
+	info = newDefaultInfo()
 
 	colorsI := make([]colorI, 0)
 
+	// Skins with skin.ini lacking Version are treated as 1.0 skins, just like in stable
+	info.Version = 1.0
+
+	var section string
+	var mania *ManiaInfo
+	overlapSet := false
+
 	for scanner.Scan() {
-		line := scanner.Text()
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSuffix(strings.TrimPrefix(line, "["), "]")
+			mania = nil
+
+			continue
+		}
 
 		tokenized := tokenize(line, ":")
 
@@ -194,6 +279,26 @@ func LoadInfo(path string) (*SkinInfo, error) {
 			continue
 		}
 
+		if section == "Mania" {
+			if tokenized[0] == "Keys" {
+				keys, kErr := strconv.Atoi(tokenized[1])
+				if kErr != nil || keys < 1 || keys > 18 {
+					return nil, fmt.Errorf("error while parsing Mania.Keys: %s", tokenized[1])
+				}
+
+				mania = newManiaInfo(keys)
+				info.Mania[keys] = mania
+			} else if mania != nil {
+				mania.parse(tokenized[0], tokenized[1])
+			}
+
+			continue
+		}
+
+		if section == "Fonts" {
+			tokenized[0] = fontKey(tokenized[0])
+		}
+
 		switch tokenized[0] {
 		case "Name":
 			info.Name = tokenized[1]
@@ -215,12 +320,24 @@ func LoadInfo(path string) (*SkinInfo, error) {
 			info.SpinnerFrequencyModulate = tokenized[1] == "1"
 		case "LayeredHitSounds":
 			info.LayeredHitSounds = tokenized[1] == "1"
+		case "ComboBurstRandom":
+			info.ComboBurstRandom = tokenized[1] == "1"
+		case "CustomComboBurstSounds":
+			for _, v := range strings.Split(tokenized[1], ",") {
+				if combo, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && combo > 0 {
+					info.CustomComboBurstSounds = append(info.CustomComboBurstSounds, combo)
+				}
+			}
 		case "CursorCentre":
 			info.CursorCentre = tokenized[1] == "1"
 		case "CursorExpand":
 			info.CursorExpand = tokenized[1] == "1"
 		case "CursorRotate":
 			info.CursorRotate = tokenized[1] == "1"
+		case "CursorTrailRotate":
+			info.CursorTrailRotate = tokenized[1] == "1"
+		case "SliderStyle":
+			info.SliderStyle = int(ParseFloat(tokenized[1], tokenized[0]))
 		case "Combo1", "Combo2", "Combo3", "Combo4", "Combo5", "Combo6", "Combo7", "Combo8":
 			index, _ := strconv.ParseInt(strings.TrimPrefix(tokenized[0], "Combo"), 10, 64)
 			colorsI = append(colorsI, colorI{
@@ -245,23 +362,45 @@ func LoadInfo(path string) (*SkinInfo, error) {
 			info.SongSelectActiveText = ParseColor(tokenized[1], tokenized[0])
 		case "InputOverlayText":
 			info.InputOverlayText = ParseColor(tokenized[1], tokenized[0])
+		case "MenuGlow":
+			info.MenuGlow = ParseColor(tokenized[1], tokenized[0])
+		case "SpinnerBackground":
+			info.SpinnerBackground = ParseColor(tokenized[1], tokenized[0])
+		case "SpinnerTextColour":
+			info.SpinnerTextColour = ParseColor(tokenized[1], tokenized[0])
+		case "StarBreakAdditive":
+			info.StarBreakAdditive = ParseColor(tokenized[1], tokenized[0])
+		case "HyperDash":
+			info.HyperDash = ParseColor(tokenized[1], tokenized[0])
+		case "HyperDashFruit":
+			col := ParseColor(tokenized[1], tokenized[0])
+			info.HyperDashFruit = &col
+		case "HyperDashAfterImage":
+			col := ParseColor(tokenized[1], tokenized[0])
+			info.HyperDashAfterImage = &col
 		case "HitCirclePrefix":
-			info.HitCirclePrefix = tokenized[1]
+			info.HitCirclePrefix = normalizePrefix(tokenized[1])
 		case "HitCircleOverlap":
 			info.HitCircleOverlap = ParseFloat(tokenized[1], tokenized[0])
+			overlapSet = true
 		case "HitCircleOverlayAboveNumber", "HitCircleOverlayAboveNumer":
 			info.HitCircleOverlayAboveNumber = tokenized[1] == "1"
 		case "ScorePrefix":
-			info.ScorePrefix = tokenized[1]
+			info.ScorePrefix = normalizePrefix(tokenized[1])
 		case "ScoreOverlap":
 			info.ScoreOverlap = ParseFloat(tokenized[1], tokenized[0])
 		case "ComboPrefix":
-			info.ComboPrefix = tokenized[1]
+			info.ComboPrefix = normalizePrefix(tokenized[1])
 		case "ComboOverlap":
 			info.ComboOverlap = ParseFloat(tokenized[1], tokenized[0])
 		}
 	}
 
+	// 1.0 skins were made for numbers drawn without overlap, stable keeps their spacing unless skin says otherwise
+	if !overlapSet && info.Version < 2.0 {
+		info.HitCircleOverlap = 0
+	}
+
 	if len(colorsI) > 0 {
 		sort.SliceStable(colorsI, func(i, j int) bool {
 			return colorsI[i].index <= colorsI[j].index
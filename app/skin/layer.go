@@ -0,0 +1,164 @@
+package skin
+
+import (
+	"github.com/tsunyoku/danser/app/settings"
+	"github.com/tsunyoku/danser/app/utils"
+	"github.com/tsunyoku/danser/framework/graphics/texture"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type layer struct {
+	name     string
+	files    *utils.FileMap
+	elements []string
+	textures map[string]*texture.TextureRegion
+
+	// info is layer's own skin.ini, font prefixes and flags of its elements come from it
+	info *SkinInfo
+}
+
+// provides checks whether the layer is allowed to supply given texture or sample
+func (l *layer) provides(name string) bool {
+	if len(l.elements) == 0 {
+		return true
+	}
+
+	name = strings.ToLower(name)
+
+	for _, pattern := range l.elements {
+		if matched, _ := filepath.Match(strings.ToLower(pattern), name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getSkinPath returns skin's directory, or its .osk archive if the skin isn't unpacked
+func getSkinPath(name string) string {
+	path := filepath.Join(settings.General.OsuSkinsDir, name)
+
+	if _, err := os.Stat(path); err != nil {
+		if _, err := os.Stat(path + ".osk"); err == nil {
+			return path + ".osk"
+		}
+	}
+
+	return path
+}
+
+// loadLayerInfo parses layer's skin.ini, skins without a valid one use default values like in osu!
+func loadLayerInfo(name string, files *utils.FileMap) *SkinInfo {
+	file, err := files.Open("skin.ini")
+	if err != nil {
+		return newDefaultInfo()
+	}
+
+	defer file.Close()
+
+	layerInfo, err := ParseInfo(file)
+	if err != nil {
+		log.Println("SkinManager: skin.ini of layer", name, "is corrupted, using default values:", err)
+		return newDefaultInfo()
+	}
+
+	return layerInfo
+}
+
+// getLayer returns the layer identified by layer bits of the source, nil if it doesn't point at one
+func getLayer(source Source) (int, *layer) {
+	layerBits := source >> layerShift
+
+	if source&SKIN == 0 || layerBits == 0 {
+		return -1, nil
+	}
+
+	for i, l := range layers {
+		if layerBits&(1<<i) > 0 {
+			return i, l
+		}
+	}
+
+	return -1, nil
+}
+
+// layerSource returns SKIN source limited to i-th layer
+func layerSource(i int) Source {
+	return SKIN | Source(1<<(layerShift+i))
+}
+
+// loadLayers builds the lookup chain from Skin.Layers. Current skin is put first unless it's listed explicitly,
+// default skin is always the last resort so it's skipped here.
+func loadLayers() {
+	layers = nil
+
+	type entry struct {
+		name     string
+		elements []string
+	}
+
+	chain := make([]entry, 0, len(settings.Skin.Layers)+1)
+
+	listed := false
+
+	for _, l := range settings.Skin.Layers {
+		if l == nil || strings.TrimSpace(l.Name) == "" {
+			continue
+		}
+
+		if strings.EqualFold(l.Name, settings.Skin.CurrentSkin) {
+			listed = true
+		}
+
+		chain = append(chain, entry{l.Name, l.Elements})
+	}
+
+	if !listed {
+		chain = append([]entry{{settings.Skin.CurrentSkin, nil}}, chain...)
+	}
+
+	for _, l := range chain {
+		if strings.EqualFold(l.name, defaultName) {
+			continue
+		}
+
+		// Current skin failed to load so it's not used for elements either
+		if strings.EqualFold(l.name, settings.Skin.CurrentSkin) && CurrentSkin == defaultName {
+			continue
+		}
+
+		if len(layers) >= maxLayers {
+			log.Println("SkinManager: Too many skin layers, skipping:", l.name)
+			continue
+		}
+
+		path := getSkinPath(l.name)
+
+		if _, err := os.Stat(path); err != nil {
+			log.Println("SkinManager: Skin layer", l.name, "does not exist, skipping...")
+			continue
+		}
+
+		files := utils.NewFileMap(path)
+
+		layerInfo := info
+		if !strings.EqualFold(l.name, CurrentSkin) {
+			layerInfo = loadLayerInfo(l.name, files)
+		}
+
+		layers = append(layers, &layer{
+			name:     l.name,
+			files:    files,
+			elements: l.elements,
+			textures: make(map[string]*texture.TextureRegion),
+			info:     layerInfo,
+		})
+
+		if len(layers) > 1 || len(l.elements) > 0 {
+			log.Println("SkinManager: Using skin layer:", l.name)
+		}
+	}
+}
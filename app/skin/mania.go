@@ -0,0 +1,148 @@
+package skin
+
+import "strings"
+
+const (
+	maniaColumnWidth     = 30.0
+	maniaColumnLineWidth = 2.0
+)
+
+// ManiaInfo holds a single [Mania] section of skin.ini.
+// Keys without typed fields (images, colours, per column flips) are kept in Values.
+type ManiaInfo struct {
+	Keys int
+
+	ColumnStart     float64
+	ColumnRight     float64
+	ColumnSpacing   []float64
+	ColumnWidth     []float64
+	ColumnLineWidth []float64
+	BarlineHeight   float64
+
+	LightingNWidth []float64
+	LightingLWidth []float64
+
+	WidthForNoteHeightScale float64
+
+	HitPosition   float64
+	LightPosition float64
+	ScorePosition float64
+	ComboPosition float64
+
+	JudgementLine       bool
+	LightFramePerSecond float64
+	SpecialStyle        int
+	ComboBurstStyle     int
+	SplitStages         bool
+	StageSeparation     float64
+	SeparateScore       bool
+	KeysUnderNotes      bool
+	UpsideDown          bool
+	NoteBodyStyle       int
+
+	Values map[string]string
+}
+
+func newManiaInfo(keys int) *ManiaInfo {
+	mania := &ManiaInfo{
+		Keys:                keys,
+		ColumnStart:         136,
+		ColumnRight:         19,
+		ColumnSpacing:       make([]float64, keys-1),
+		ColumnWidth:         make([]float64, keys),
+		ColumnLineWidth:     make([]float64, keys+1),
+		BarlineHeight:       1.2,
+		LightingNWidth:      make([]float64, keys),
+		LightingLWidth:      make([]float64, keys),
+		HitPosition:         402,
+		LightPosition:       413,
+		ScorePosition:       325,
+		ComboPosition:       111,
+		JudgementLine:       true,
+		LightFramePerSecond: 60,
+		ComboBurstStyle:     1,
+		StageSeparation:     40,
+		SeparateScore:       true,
+		Values:              make(map[string]string),
+	}
+
+	for i := range mania.ColumnWidth {
+		mania.ColumnWidth[i] = maniaColumnWidth
+	}
+
+	for i := range mania.ColumnLineWidth {
+		mania.ColumnLineWidth[i] = maniaColumnLineWidth
+	}
+
+	return mania
+}
+
+func (mania *ManiaInfo) parse(key, value string) {
+	mania.Values[key] = value
+
+	switch key {
+	case "ColumnStart":
+		mania.ColumnStart = ParseFloat(value, "Mania."+key)
+	case "ColumnRight":
+		mania.ColumnRight = ParseFloat(value, "Mania."+key)
+	case "ColumnSpacing":
+		parseFloatList(value, key, mania.ColumnSpacing)
+	case "ColumnWidth":
+		parseFloatList(value, key, mania.ColumnWidth)
+	case "ColumnLineWidth":
+		parseFloatList(value, key, mania.ColumnLineWidth)
+	case "BarlineHeight":
+		mania.BarlineHeight = ParseFloat(value, "Mania."+key)
+	case "LightingNWidth":
+		parseFloatList(value, key, mania.LightingNWidth)
+	case "LightingLWidth":
+		parseFloatList(value, key, mania.LightingLWidth)
+	case "WidthForNoteHeightScale":
+		mania.WidthForNoteHeightScale = ParseFloat(value, "Mania."+key)
+	case "HitPosition":
+		mania.HitPosition = ParseFloat(value, "Mania."+key)
+	case "LightPosition":
+		mania.LightPosition = ParseFloat(value, "Mania."+key)
+	case "ScorePosition":
+		mania.ScorePosition = ParseFloat(value, "Mania."+key)
+	case "ComboPosition":
+		mania.ComboPosition = ParseFloat(value, "Mania."+key)
+	case "JudgementLine":
+		mania.JudgementLine = value == "1"
+	case "LightFramePerSecond":
+		mania.LightFramePerSecond = ParseFloat(value, "Mania."+key)
+	case "SpecialStyle":
+		mania.SpecialStyle = int(ParseFloat(value, "Mania."+key))
+	case "ComboBurstStyle":
+		mania.ComboBurstStyle = int(ParseFloat(value, "Mania."+key))
+	case "SplitStages":
+		mania.SplitStages = value == "1"
+	case "StageSeparation":
+		mania.StageSeparation = ParseFloat(value, "Mania."+key)
+	case "SeparateScore":
+		mania.SeparateScore = value == "1"
+	case "KeysUnderNotes":
+		mania.KeysUnderNotes = value == "1"
+	case "UpsideDown":
+		mania.UpsideDown = value == "1"
+	case "NoteBodyStyle":
+		mania.NoteBodyStyle = int(ParseFloat(value, "Mania."+key))
+	}
+}
+
+// Get returns raw value of a key without a typed field, like KeyImage0 or Colour1
+func (mania *ManiaInfo) Get(key string) (string, bool) {
+	value, exists := mania.Values[key]
+	return value, exists
+}
+
+// parseFloatList fills per column values, stable ignores values beyond the column count
+func parseFloatList(text, errType string, target []float64) {
+	for i, v := range strings.Split(text, ",") {
+		if i >= len(target) {
+			break
+		}
+
+		target[i] = ParseFloat(strings.TrimSpace(v), "Mania."+errType)
+	}
+}
@@ -1,6 +1,7 @@
 package skin
 
 import (
+	"errors"
 	"fmt"
 	"github.com/faiface/mainthread"
 	"github.com/tsunyoku/danser/app/settings"
@@ -30,6 +31,12 @@ const (
 
 const defaultName = "default"
 
+// layerShift is the first Source bit identifying a skin layer, SKIN sources without layer bits match all layers
+const (
+	layerShift = 4
+	maxLayers  = 32
+)
+
 var fontLock = &sync.Mutex{}
 var soundLock = &sync.Mutex{}
 var textureLock = &sync.Mutex{}
@@ -38,7 +45,6 @@ var atlas *texture.TextureAtlas
 
 var animationCache = make(map[string][]*texture.TextureRegion)
 
-var skinCache = make(map[string]*texture.TextureRegion)
 var defaultCache = make(map[string]*texture.TextureRegion)
 
 var sourceCache = make(map[*texture.TextureRegion]Source)
@@ -47,7 +53,10 @@ var fontCache = make(map[string]*font.Font)
 
 var sampleCache = make(map[string]*bass.Sample)
 
-var pathCache *utils.FileMap
+var sampleSources = make(map[*bass.Sample]Source)
+
+// layers are user skins consulted in order before falling back to the default skin
+var layers []*layer
 
 var CurrentSkin = defaultName
 
@@ -77,13 +86,15 @@ func checkInit() {
 	if CurrentSkin == defaultName {
 		fallback()
 	} else {
-		pathCache = utils.NewFileMap(filepath.Join(settings.General.OsuSkinsDir, CurrentSkin))
+		files := utils.NewFileMap(getSkinPath(CurrentSkin))
 
-		path, err := pathCache.GetFile("skin.ini")
+		file, err := files.Open("skin.ini")
 		if err == nil {
-			if info, err = LoadInfo(path); err != nil {
+			if info, err = ParseInfo(file); err != nil {
 				log.Println("SkinManager:", CurrentSkin, "is corrupted, falling back to default...")
 			}
+
+			file.Close()
 		} else {
 			log.Println("skin.ini does not exist! Falling back to default...")
 		}
@@ -93,6 +104,8 @@ func checkInit() {
 		}
 	}
 
+	loadLayers()
+
 	log.Println(fmt.Sprintf("SkinManager: Skin \"%s\" loaded.", CurrentSkin))
 }
 
@@ -101,6 +114,31 @@ func GetInfo() *SkinInfo {
 	return info
 }
 
+// GetInfoFor returns skin.ini of the layer that supplies given element, so flags and colours match its textures
+func GetInfoFor(element string) *SkinInfo {
+	checkInit()
+
+	if _, l := getLayer(GetSource(element)); l != nil {
+		return l.info
+	}
+
+	return info
+}
+
+// getFontPrefix returns texture prefix and overlap of the font from given skin.ini
+func getFontPrefix(name string, fInfo *SkinInfo) (string, float64) {
+	switch name {
+	case defaultName:
+		return fInfo.HitCirclePrefix, fInfo.HitCircleOverlap
+	case "score":
+		return fInfo.ScorePrefix, fInfo.ScoreOverlap
+	case "combo":
+		return fInfo.ComboPrefix, fInfo.ComboOverlap
+	}
+
+	return name, 0
+}
+
 func GetFont(name string) *font.Font {
 	checkInit()
 
@@ -111,36 +149,35 @@ func GetFont(name string) *font.Font {
 		return fnt
 	}
 
-	overlap := 0.0
+	prefix, overlap := getFontPrefix(name, info)
+	source := ALL
 
-	prefix := name
+	// Digits come from the first layer that has them under its own prefix, missing characters fall back to default skin
+	for i, l := range layers {
+		lPrefix, lOverlap := getFontPrefix(name, l.info)
 
-	switch name {
-	case defaultName:
-		prefix = info.HitCirclePrefix
-		overlap = info.HitCircleOverlap
-	case "score":
-		prefix = info.ScorePrefix
-		overlap = info.ScoreOverlap
-	case "combo":
-		prefix = info.ComboPrefix
-		overlap = info.ComboOverlap
+		if GetTextureSource(lPrefix+"-0", layerSource(i)) != nil {
+			prefix, overlap = lPrefix, lOverlap
+			source = layerSource(i) | LOCAL
+
+			break
+		}
 	}
 
-	if name == "scoreentry" && GetTexture(prefix+"-0") == nil {
+	if name == "scoreentry" && GetTextureSource(prefix+"-0", source) == nil {
 		return nil
 	}
 
 	chars := make(map[rune]*texture.TextureRegion)
 
 	for i := '0'; i <= '9'; i++ {
-		chars[i] = GetTexture(prefix + "-" + string(i))
+		chars[i] = GetTextureSource(prefix+"-"+string(i), source)
 	}
 
-	chars[','] = GetTexture(prefix + "-comma")
-	chars['.'] = GetTexture(prefix + "-dot")
-	chars['%'] = GetTexture(prefix + "-percent")
-	chars['x'] = GetTexture(prefix + "-x")
+	chars[','] = GetTextureSource(prefix+"-comma", source)
+	chars['.'] = GetTextureSource(prefix+"-dot", source)
+	chars['%'] = GetTextureSource(prefix+"-percent", source)
+	chars['x'] = GetTextureSource(prefix+"-x", source)
 
 	fnt := font.LoadTextureFontMap2(chars, overlap)
 
@@ -161,21 +198,27 @@ func GetTextureSource(name string, source Source) *texture.TextureRegion {
 
 	source = source & (^BEATMAP)
 
-	if CurrentSkin == defaultName {
-		source = source & (^SKIN)
-	}
-
 	if source&SKIN > 0 {
-		if rg, exists := skinCache[name]; exists {
-			if rg != nil {
-				return rg
+		layerBits := source >> layerShift
+
+		for i, l := range layers {
+			if (layerBits != 0 && layerBits&(1<<i) == 0) || !l.provides(name) {
+				continue
 			}
-		} else {
-			rg := loadTexture(name+".png", false)
-			skinCache[name] = rg
+
+			if rg, exists := l.textures[name]; exists {
+				if rg != nil {
+					return rg
+				}
+
+				continue
+			}
+
+			rg := loadTexture(l.files, name+".png")
+			l.textures[name] = rg
 
 			if rg != nil {
-				sourceCache[rg] = SKIN
+				sourceCache[rg] = layerSource(i)
 				return rg
 			}
 		}
@@ -186,7 +229,7 @@ func GetTextureSource(name string, source Source) *texture.TextureRegion {
 			return rg
 		}
 
-		rg := loadTexture(name+".png", true)
+		rg := loadTexture(nil, name+".png")
 		defaultCache[name] = rg
 
 		if rg != nil {
@@ -242,9 +285,10 @@ func GetMostSpecific(rg1, rg2 *texture.TextureRegion) *texture.TextureRegion {
 	rg1S := sourceCache[rg1]
 	rg2S := sourceCache[rg2]
 
+	// earlier skin layers have lower layer bits so they win over later ones
 	if rg1S == BEATMAP ||
-		rg1S == SKIN && rg2S != BEATMAP ||
-		rg1S == LOCAL && rg2S != BEATMAP && rg2S != SKIN {
+		rg1S&SKIN > 0 && rg2S != BEATMAP && (rg2S&SKIN == 0 || rg1S <= rg2S) ||
+		rg1S == LOCAL && rg2S != BEATMAP && rg2S&SKIN == 0 {
 		return rg1
 	}
 
@@ -275,29 +319,34 @@ func checkAtlas() {
 	}
 }
 
-func getPixmap(name string, local bool) (*texture.Pixmap, error) {
-	if local {
+// getPixmap loads the image from skin files, nil files mean the built-in default skin
+func getPixmap(files *utils.FileMap, name string) (*texture.Pixmap, error) {
+	if files == nil {
 		return assets.GetPixmap(filepath.Join("assets", "default-skin", name))
 	}
 
-	path, err := pathCache.GetFile(name)
+	data, err := files.ReadFile(name)
 	if err != nil {
 		return nil, err
 	}
 
-	return texture.NewPixmapFileString(path)
+	if len(data) == 0 {
+		return nil, errors.New("empty file")
+	}
+
+	return texture.NewPixmapFromBytes(data)
 }
 
-func loadTexture(name string, local bool) *texture.TextureRegion {
+func loadTexture(files *utils.FileMap, name string) *texture.TextureRegion {
 	ext := filepath.Ext(name)
 
 	x2Name := strings.TrimSuffix(name, ext) + "@2x" + ext
 
 	var region *texture.TextureRegion
 
-	image, err := getPixmap(x2Name, local)
+	image, err := getPixmap(files, x2Name)
 	if err != nil {
-		image, err = getPixmap(name, local)
+		image, err = getPixmap(files, name)
 		if err == nil {
 			region = &texture.TextureRegion{}
 			region.Width = float32(image.Width)
@@ -357,12 +406,21 @@ func GetSample(name string) *bass.Sample {
 
 	var sample *bass.Sample
 
-	if CurrentSkin != defaultName {
-		sample = tryLoad(name, false)
+	for i, l := range layers {
+		if !l.provides(name) {
+			continue
+		}
+
+		if sample = tryLoad(l.files, name); sample != nil {
+			sampleSources[sample] = layerSource(i)
+			break
+		}
 	}
 
 	if sample == nil {
-		sample = tryLoad(name, true)
+		if sample = tryLoad(nil, name); sample != nil {
+			sampleSources[sample] = LOCAL
+		}
 	}
 
 	sampleCache[name] = sample
@@ -370,8 +428,8 @@ func GetSample(name string) *bass.Sample {
 	return sample
 }
 
-func getSample(name string, local bool) *bass.Sample {
-	if local {
+func getSample(files *utils.FileMap, name string) *bass.Sample {
+	if files == nil {
 		data, err := assets.GetBytes(filepath.Join("assets", "default-skin", name))
 		if err != nil {
 			return nil
@@ -380,24 +438,24 @@ func getSample(name string, local bool) *bass.Sample {
 		return bass.NewSampleData(data)
 	}
 
-	path, err := pathCache.GetFile(name)
+	data, err := files.ReadFile(name)
 	if err != nil {
 		return nil
 	}
 
-	return bass.NewSample(path)
+	return bass.NewSampleData(data)
 }
 
-func tryLoad(basePath string, local bool) *bass.Sample {
-	if sam := getSample(basePath+".wav", local); sam != nil {
+func tryLoad(files *utils.FileMap, basePath string) *bass.Sample {
+	if sam := getSample(files, basePath+".wav"); sam != nil {
 		return sam
 	}
 
-	if sam := getSample(basePath+".ogg", local); sam != nil {
+	if sam := getSample(files, basePath+".ogg"); sam != nil {
 		return sam
 	}
 
-	if sam := getSample(basePath+".mp3", local); sam != nil {
+	if sam := getSample(files, basePath+".mp3"); sam != nil {
 		return sam
 	}
 
@@ -434,7 +492,7 @@ func GetColors() []color.Color {
 		return beatmapColors
 	}
 
-	return info.ComboColors
+	return GetInfoFor("hitcircle").ComboColors
 }
 
 func GetColor(comboSet, comboSetHax int, base color.Color) (col color.Color) {
@@ -455,8 +513,8 @@ func GetColor(comboSet, comboSetHax int, base color.Color) (col color.Color) {
 
 		if settings.Objects.Colors.UseBeatmapComboColors && len(beatmapColors) > 0 {
 			col = beatmapColors[cSet%len(beatmapColors)]
-		} else if settings.Objects.Colors.UseSkinComboColors && len(info.ComboColors) > 0 {
-			col = info.ComboColors[cSet%len(info.ComboColors)]
+		} else if skinColors := GetInfoFor("hitcircle").ComboColors; settings.Objects.Colors.UseSkinComboColors && len(skinColors) > 0 {
+			col = skinColors[cSet%len(skinColors)]
 		} else if settings.Objects.Colors.UseComboColors && len(settings.Objects.Colors.ComboColors) > 0 {
 			cHSV := settings.Objects.Colors.ComboColors[cSet%len(settings.Objects.Colors.ComboColors)]
 			r, g, b := color.HSVToRGB(float32(cHSV.Hue), float32(cHSV.Saturation), float32(cHSV.Value))
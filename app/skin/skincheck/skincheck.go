@@ -0,0 +1,285 @@
+package skincheck
+
+import (
+	"fmt"
+	"github.com/faiface/mainthread"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/tsunyoku/danser/app/bmath"
+	camera2 "github.com/tsunyoku/danser/app/bmath/camera"
+	"github.com/tsunyoku/danser/app/graphics"
+	"github.com/tsunyoku/danser/app/skin"
+	"github.com/tsunyoku/danser/app/utils"
+	"github.com/tsunyoku/danser/framework/graphics/batch"
+	"github.com/tsunyoku/danser/framework/graphics/blend"
+	"github.com/tsunyoku/danser/framework/graphics/buffer"
+	"github.com/tsunyoku/danser/framework/graphics/font"
+	"github.com/tsunyoku/danser/framework/graphics/viewport"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"golang.org/x/image/draw"
+	xfont "golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	columns     = 12
+	cellSize    = 176
+	labelHeight = 32
+	cellPadding = 12
+)
+
+var (
+	backgroundColor = color.NRGBA{R: 26, G: 26, B: 26, A: 255}
+	cellColor       = color.NRGBA{R: 46, G: 46, B: 46, A: 255}
+	problemColor    = color.NRGBA{R: 110, G: 30, B: 30, A: 255}
+	missingColor    = color.NRGBA{R: 60, G: 60, B: 90, A: 255}
+)
+
+// Run validates current skin, prints the report and saves a contact sheet of all elements to screenshots folder.
+// It has to be called outside of the main thread, the same way render loops are.
+func Run(output string) {
+	report := skin.Check()
+
+	if output == "" {
+		output = "skin-check_" + report.Skin
+	}
+
+	if err := writeReport(report, os.Stdout); err != nil {
+		log.Println("Failed to print the skin report:", err)
+	}
+
+	if err := saveReport(report, output); err != nil {
+		log.Println("Failed to save the skin report:", err)
+	}
+
+	width := columns * cellSize
+	height := int(math.Ceil(float64(len(report.Elements))/columns)) * (cellSize + labelHeight)
+
+	rendered := false
+
+	mainthread.Call(func() {
+		rendered = renderGL(report, width, height, output)
+	})
+
+	if !rendered {
+		log.Println("Contact sheet is too big for the GPU, composing it on CPU...")
+
+		if err := renderCPU(report, width, height, output); err != nil {
+			log.Println("Failed to save the contact sheet:", err)
+		}
+	}
+
+	log.Println(fmt.Sprintf("Skin check of \"%s\" complete, %d problem(s) found.", report.Skin, report.ProblemCount()))
+}
+
+func writeReport(report *skin.CheckReport, writer io.Writer) error {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(table, "Skin: %s\n\n", report.Skin)
+	fmt.Fprintln(table, "Element\tSource\tFrames\tProblems\t")
+
+	for _, e := range report.Elements {
+		fmt.Fprintf(table, "%s\t%s\t%d\t%s\t\n", e.Name, e.Source, len(e.Frames), strings.Join(e.Problems, "; "))
+	}
+
+	fmt.Fprintln(table, "\t\t\t\t")
+	fmt.Fprintln(table, "Sample\tSource\t\tProblems\t")
+
+	for _, s := range report.Samples {
+		fmt.Fprintf(table, "%s\t%s\t\t%s\t\n", s.Name, s.Source, strings.Join(s.Problems, "; "))
+	}
+
+	return table.Flush()
+}
+
+// saveReport saves the text report next to the contact sheet
+func saveReport(report *skin.CheckReport, output string) error {
+	if err := os.Mkdir("screenshots", 0755); err != nil && !os.IsExist(err) {
+		return err
+	}
+
+	file, err := os.Create(filepath.Join("screenshots", output+".txt"))
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	return writeReport(report, file)
+}
+
+func cellPosition(i int) (float64, float64) {
+	return float64((i % columns) * cellSize), float64((i / columns) * (cellSize + labelHeight))
+}
+
+func getCellColor(element *skin.ElementCheck) color.NRGBA {
+	if len(element.Problems) > 0 {
+		return problemColor
+	} else if len(element.Frames) == 0 {
+		return missingColor
+	}
+
+	return cellColor
+}
+
+func getLabel(element *skin.ElementCheck) string {
+	if len(element.Frames) > 1 {
+		return fmt.Sprintf("%s (%d)", element.Name, len(element.Frames))
+	}
+
+	return element.Name
+}
+
+// renderGL draws the sheet using regular texture atlas and saves it with utils.MakeScreenshot, returns false if framebuffer can't fit the sheet
+func renderGL(report *skin.CheckReport, width, height int, output string) bool {
+	var maxSize int32
+	gl.GetIntegerv(gl.MAX_RENDERBUFFER_SIZE, &maxSize)
+
+	if width > int(maxSize) || height > int(maxSize) {
+		return false
+	}
+
+	fbo := buffer.NewFrameMultisampleScreen(width, height, false, 0)
+	fbo.Bind()
+
+	viewport.Push(width, height)
+
+	blend.Enable()
+	blend.SetFunction(blend.One, blend.OneMinusSrcAlpha)
+
+	gl.ClearColor(float32(backgroundColor.R)/255, float32(backgroundColor.G)/255, float32(backgroundColor.B)/255, 1)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+
+	camera := camera2.NewCamera()
+	camera.SetViewport(width, height, true)
+	camera.SetOrigin(vector.NewVec2d(float64(width)/2, float64(height)/2))
+	camera.Update()
+
+	renderer := batch.NewQuadBatch()
+	renderer.Begin()
+	renderer.SetCamera(camera.GetProjectionView())
+
+	fnt := font.GetFont("Exo 2 Bold")
+
+	pixel := graphics.Pixel.GetRegion()
+
+	for i, e := range report.Elements {
+		x, y := cellPosition(i)
+		centre := vector.NewVec2d(x+cellSize/2, y+cellSize/2)
+
+		col := getCellColor(e)
+
+		renderer.ResetTransform()
+		renderer.SetColor(float64(col.R)/255, float64(col.G)/255, float64(col.B)/255, 1)
+		renderer.SetTranslation(vector.NewVec2d(x+cellSize/2, y+(cellSize+labelHeight)/2))
+		renderer.SetScale(cellSize/2-1, (cellSize+labelHeight)/2-1)
+		renderer.DrawUnit(pixel)
+
+		if len(e.Frames) > 0 {
+			frame := e.Frames[0]
+
+			scale := math.Min(1, (cellSize-cellPadding)/math.Max(float64(frame.Width), float64(frame.Height)))
+
+			renderer.ResetTransform()
+			renderer.SetColor(1, 1, 1, 1)
+			renderer.SetTranslation(centre)
+			renderer.SetScale(scale, scale)
+			renderer.DrawTexture(*frame)
+		}
+
+		renderer.ResetTransform()
+		renderer.SetColor(1, 1, 1, 1)
+		fnt.DrawOrigin(renderer, x+cellSize/2, y+cellSize+labelHeight/4, bmath.Origin.Centre, 12, false, getLabel(e))
+
+		renderer.SetColor(0.7, 0.7, 0.7, 1)
+		fnt.DrawOrigin(renderer, x+cellSize/2, y+cellSize+labelHeight*3/4, bmath.Origin.Centre, 10, false, e.Source)
+	}
+
+	renderer.End()
+
+	utils.MakeScreenshot(width, height, output, false)
+
+	blend.ClearStack()
+	viewport.Pop()
+
+	fbo.Unbind()
+	fbo.Dispose()
+
+	return true
+}
+
+// renderCPU composes the sheet from raw images of elements
+func renderCPU(report *skin.CheckReport, width, height int, output string) error {
+	sheet := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	draw.Draw(sheet, sheet.Bounds(), image.NewUniform(backgroundColor), image.Point{}, draw.Src)
+
+	for i, e := range report.Elements {
+		xF, yF := cellPosition(i)
+		x, y := int(xF), int(yF)
+
+		draw.Draw(sheet, image.Rect(x+1, y+1, x+cellSize-1, y+cellSize+labelHeight-1), image.NewUniform(getCellColor(e)), image.Point{}, draw.Src)
+
+		if pixmap, hd, err := e.LoadPixmap(); err == nil {
+			img := pixmap.NRGBA()
+
+			w, h := float64(pixmap.Width), float64(pixmap.Height)
+			if hd {
+				w, h = w/2, h/2
+			}
+
+			scale := math.Min(1, (cellSize-cellPadding)/math.Max(w, h))
+
+			dw, dh := int(math.Max(1, w*scale)), int(math.Max(1, h*scale))
+			cX, cY := x+cellSize/2, y+cellSize/2
+
+			draw.ApproxBiLinear.Scale(sheet, image.Rect(cX-dw/2, cY-dh/2, cX-dw/2+dw, cY-dh/2+dh), img, img.Bounds(), draw.Over, nil)
+
+			pixmap.Dispose()
+		}
+
+		drawText(sheet, x+cellSize/2, y+cellSize+12, getLabel(e), color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+		drawText(sheet, x+cellSize/2, y+cellSize+27, e.Source, color.NRGBA{R: 180, G: 180, B: 180, A: 255})
+	}
+
+	if err := os.Mkdir("screenshots", 0755); err != nil && !os.IsExist(err) {
+		return err
+	}
+
+	file, err := os.Create(filepath.Join("screenshots", output+".png"))
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	if err = png.Encode(file, sheet); err != nil {
+		return err
+	}
+
+	log.Println("Contact sheet", output+".png", "saved!")
+
+	return nil
+}
+
+// drawText draws centered text with a bitmap font
+func drawText(dst draw.Image, x, baseline int, text string, col color.Color) {
+	drawer := &xfont.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(col),
+		Face: basicfont.Face7x13,
+	}
+
+	drawer.Dot = fixed.P(x-drawer.MeasureString(text).Round()/2, baseline)
+	drawer.DrawString(text)
+}
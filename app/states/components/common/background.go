@@ -21,7 +21,6 @@ import (
 	"github.com/tsunyoku/danser/framework/math/vector"
 	"log"
 	"math"
-	"path/filepath"
 )
 
 type Background struct {
@@ -62,7 +61,7 @@ func NewBackground() *Background {
 
 func (bg *Background) SetBeatmap(beatMap *beatmap.BeatMap, loadStoryboards bool) {
 	go func() {
-		image, err := texture.NewPixmapFileString(filepath.Join(settings.General.OsuSongsDir, beatMap.Dir, beatMap.Bg))
+		image, err := beatMap.GetPixmap(beatMap.Bg)
 		if err != nil {
 			image, err = assets.GetPixmap("assets/textures/background-1.png")
 			if err != nil {
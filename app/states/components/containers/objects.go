@@ -177,6 +177,10 @@ func (container *HitObjectContainer) Draw(batch *batch.QuadBatch, cameras []mgl3
 			bodyColors = settings.Objects.Colors.Sliders.Body.Color.GetColors(divides, float64(scale), float64(alpha))
 		}
 
+		objectColors = settings.Playfield.Symmetry.AdjustColors(objectColors, 1)
+		borderColors = settings.Playfield.Symmetry.AdjustColors(borderColors, 1)
+		bodyColors = settings.Playfield.Symmetry.AdjustColors(bodyColors, 1)
+
 		if !settings.Objects.ScaleToTheBeat {
 			scale = 1
 		}
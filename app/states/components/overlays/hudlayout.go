@@ -0,0 +1,137 @@
+package overlays
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/tsunyoku/danser/app/settings"
+	"github.com/tsunyoku/danser/app/storyboard"
+	"github.com/tsunyoku/danser/framework/graphics/batch"
+	"github.com/tsunyoku/danser/framework/math/animation"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"math"
+	"strings"
+)
+
+const hudFadeTime = 200.0
+
+// hudBox is an approximate area a HUD component occupies with its default placement
+type hudBox struct {
+	min, max vector.Vector2d
+}
+
+// newHUDBox creates a box of given size placed at position with origin in -1..1 range, like sprites do
+func newHUDBox(position vector.Vector2d, width, height float64, origin vector.Vector2d) hudBox {
+	size := vector.NewVec2d(width, height)
+	min := position.Sub(size.Mult(origin.AddS(1, 1).Scl(0.5)))
+
+	return hudBox{min: min, max: min.Add(size)}
+}
+
+func (box hudBox) point(origin vector.Vector2d) vector.Vector2d {
+	return box.min.Add(box.max.Sub(box.min).Mult(origin.AddS(1, 1).Scl(0.5)))
+}
+
+type hudFade struct {
+	glider  *animation.Glider
+	visible bool
+}
+
+// updateHUDVisibility fades components in and out when game enters states they're limited to
+func (overlay *ScoreOverlay) updateHUDVisibility(time float64) {
+	if overlay.hudFades == nil {
+		overlay.hudFades = make(map[string]*hudFade)
+	}
+
+	states := overlay.getHUDStates()
+
+	overlay.hudComponents = settings.HUDLayout.GetComponents()
+
+	for name, component := range overlay.hudComponents {
+		visible := len(component.ShowIn) == 0
+
+		for _, s := range component.ShowIn {
+			visible = visible || states[strings.ToLower(s)]
+		}
+
+		fade, ok := overlay.hudFades[name]
+		if !ok {
+			fade = &hudFade{glider: animation.NewGlider(0)}
+			fade.glider.SetValue(visibilityValue(visible))
+			fade.visible = visible
+
+			overlay.hudFades[name] = fade
+		}
+
+		if fade.visible != visible {
+			fade.glider.AddEvent(time, time+hudFadeTime, visibilityValue(visible))
+			fade.visible = visible
+		}
+
+		fade.glider.Update(time)
+	}
+}
+
+func visibilityValue(visible bool) float64 {
+	if visible {
+		return 1
+	}
+
+	return 0
+}
+
+func (overlay *ScoreOverlay) getHUDStates() map[string]bool {
+	results := overlay.panel != nil && overlay.resultsFade.GetValue() > 0.001
+	kiai := overlay.ruleset.GetBeatMap().Timings.GetPoint(overlay.audioTime).Kiai
+
+	return map[string]bool{
+		"playing": !results && !overlay.breakMode,
+		"break":   !results && overlay.breakMode,
+		"kiai":    !results && !overlay.breakMode && kiai,
+		"results": results,
+	}
+}
+
+// getLayoutMatrix moves, rotates and scales the component drawn in box according to its layout entry
+func (overlay *ScoreOverlay) getLayoutMatrix(component *settings.HUDComponent, box hudBox) mgl32.Mat4 {
+	pivot := box.point(vector.NewVec2d(0, 0))
+	target := pivot
+
+	if origin, ok := storyboard.Origin[component.Anchor]; ok {
+		pivot = box.point(origin)
+		target = vector.NewVec2d(overlay.ScaledWidth, overlay.ScaledHeight).Mult(origin.AddS(1, 1).Scl(0.5))
+	}
+
+	target = target.AddS(component.XOffset, component.YOffset)
+
+	scale := float32(component.Scale)
+
+	return mgl32.Translate3D(target.X32(), target.Y32(), 0).
+		Mul4(mgl32.HomogRotate3DZ(float32(component.Rotation * math.Pi / 180))).
+		Mul4(mgl32.Scale3D(scale, scale, 1)).
+		Mul4(mgl32.Translate3D(-pivot.X32(), -pivot.Y32(), 0))
+}
+
+// beginHUDComponent sets up cameras for the component, returned value is its opacity multiplier
+func (overlay *ScoreOverlay) beginHUDComponent(batch *batch.QuadBatch, name string, box hudBox) float64 {
+	component, ok := overlay.hudComponents[name]
+	if !ok {
+		return 1
+	}
+
+	projection := overlay.camera.GetProjectionView().Mul4(overlay.getLayoutMatrix(component, box))
+
+	batch.SetCamera(projection)
+	overlay.shapeRenderer.SetCamera(projection)
+
+	alpha := component.Opacity
+
+	if fade, ok := overlay.hudFades[name]; ok {
+		alpha *= fade.glider.GetValue()
+	}
+
+	return alpha
+}
+
+func (overlay *ScoreOverlay) endHUDComponent(batch *batch.QuadBatch) {
+	batch.SetCamera(overlay.camera.GetProjectionView())
+	overlay.shapeRenderer.SetCamera(overlay.camera.GetProjectionView())
+}
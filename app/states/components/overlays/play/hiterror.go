@@ -41,6 +41,14 @@ type HitErrorMeter struct {
 	urGlider *animation.TargetGlider
 }
 
+// GetBounds returns the area of the meter with unstable rate text above it, used to place the meter by HUD layout
+func (meter *HitErrorMeter) GetBounds() (vector.Vector2d, vector.Vector2d) {
+	scale := settings.Gameplay.HitErrorMeter.Scale
+	halfWidth := float64(meter.diff.Hit50) * 0.8 * scale
+
+	return vector.NewVec2d(meter.Width/2-halfWidth, meter.Height-errorBase*8*scale), vector.NewVec2d(meter.Width/2+halfWidth, meter.Height)
+}
+
 func NewHitErrorMeter(width, height float64, diff *difficulty.Difficulty) *HitErrorMeter {
 	meter := new(HitErrorMeter)
 	meter.Width = width
@@ -224,4 +232,29 @@ func (meter *HitErrorMeter) GetUnstableRate() float64 {
 
 func (meter *HitErrorMeter) GetUnstableRateConverted() float64 {
 	return meter.unstableRate / meter.diff.Speed
+}
+
+// GetHistogram counts hit errors in bins spanning the 50 hit window, values are normalized to the biggest bin
+func (meter *HitErrorMeter) GetHistogram(bins int) []float64 {
+	histogram := make([]float64, bins)
+
+	window := float64(meter.diff.Hit50)
+	binWidth := 2 * window / float64(bins)
+
+	maxCount := 0.0
+
+	for _, e := range meter.errors {
+		bin := bmath.ClampI(int((e+window)/binWidth), 0, bins-1)
+
+		histogram[bin]++
+		maxCount = math.Max(maxCount, histogram[bin])
+	}
+
+	if maxCount > 0 {
+		for i := range histogram {
+			histogram[i] /= maxCount
+		}
+	}
+
+	return histogram
 }
\ No newline at end of file
@@ -152,6 +152,17 @@ func (hpBar *HpBar) Draw(batch *batch.QuadBatch, alpha float64) {
 	hpBar.explodes.Draw(hpBar.lastTime, batch)
 }
 
+// GetSize returns the size of the bar background, used to place the bar by HUD layout
+func (hpBar *HpBar) GetSize() vector.Vector2d {
+	if hpBar.healthBackground.Textures[0] == nil {
+		return vector.NewVec2d(0, 0)
+	}
+
+	tex := hpBar.healthBackground.Textures[0]
+
+	return vector.NewVec2d(float64(tex.Width), float64(tex.Height)).Scl(settings.Gameplay.HpBar.Scale)
+}
+
 func (hpBar *HpBar) SlideOut() {
 	hpBar.hpSlide.AddEvent(hpBar.lastTime, hpBar.lastTime+500, -20)
 	hpBar.hpFade.AddEvent(hpBar.lastTime, hpBar.lastTime+500, 0)
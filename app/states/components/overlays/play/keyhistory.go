@@ -0,0 +1,257 @@
+package play
+
+import (
+	"fmt"
+	"github.com/tsunyoku/danser/app/bmath"
+	"github.com/tsunyoku/danser/app/graphics"
+	"github.com/tsunyoku/danser/app/settings"
+	"github.com/tsunyoku/danser/framework/graphics/batch"
+	"github.com/tsunyoku/danser/framework/graphics/font"
+	"github.com/tsunyoku/danser/framework/graphics/shape"
+	color2 "github.com/tsunyoku/danser/framework/math/color"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"math"
+	"strconv"
+)
+
+const (
+	laneHeight  = 36.0
+	laneSpacing = 6.0
+	keyBoxWidth = 48.0
+	infoHeight  = 20.0
+)
+
+var keyNames = [4]string{"K1", "K2", "M1", "M2"}
+
+var keyColors = [4]color2.Color{
+	{R: 1.0, G: 222.0 / 255, B: 0, A: 1},
+	{R: 1.0, G: 222.0 / 255, B: 0, A: 1},
+	{R: 248.0 / 255, G: 0, B: 158.0 / 255, A: 1},
+	{R: 248.0 / 255, G: 0, B: 158.0 / 255, A: 1},
+}
+
+type keyPress struct {
+	start, end float64
+	held       bool
+}
+
+// KeyHistory is a streaming style key overlay with scrolling press bars, keys per second and press durations.
+// It reads key states of the cursor directly so it works with every controller.
+type KeyHistory struct {
+	cursor *graphics.Cursor
+
+	Width  float64
+	Height float64
+
+	time float64
+
+	states   [4]bool
+	presses  [4][]*keyPress
+	counts   [4]int
+	holdSum  [4]float64
+	released [4]int
+
+	recent []float64
+	maxKPS int
+
+	shapeRenderer *shape.Renderer
+}
+
+func NewKeyHistory(cursor *graphics.Cursor, width, height float64) *KeyHistory {
+	return &KeyHistory{
+		cursor: cursor,
+		Width:  width,
+		Height: height,
+	}
+}
+
+func (history *KeyHistory) Update(time float64) {
+	if time < history.time {
+		history.time = time
+		return
+	}
+
+	history.time = time
+
+	cursor := history.cursor
+	currentStates := [4]bool{cursor.LeftKey, cursor.RightKey, cursor.LeftMouse && !cursor.LeftKey, cursor.RightMouse && !cursor.RightKey}
+
+	for i, state := range currentStates {
+		if !history.states[i] && state {
+			history.presses[i] = append(history.presses[i], &keyPress{start: time, end: time, held: true})
+			history.counts[i]++
+
+			history.recent = append(history.recent, time)
+		}
+
+		if history.states[i] && !state && len(history.presses[i]) > 0 {
+			press := history.presses[i][len(history.presses[i])-1]
+			press.held = false
+
+			history.holdSum[i] += press.end - press.start
+			history.released[i]++
+		}
+
+		history.states[i] = state
+	}
+
+	visible := settings.Gameplay.KeyOverlay.BarLength / math.Max(settings.Gameplay.KeyOverlay.BarSpeed, 1) * 1000
+
+	for i := range history.presses {
+		toRemove := 0
+
+		for j, press := range history.presses[i] {
+			if press.held {
+				press.end = time
+			}
+
+			if !press.held && time-press.end > visible && j == toRemove {
+				toRemove++
+			}
+		}
+
+		history.presses[i] = history.presses[i][toRemove:]
+	}
+
+	toRemove := 0
+	for toRemove < len(history.recent) && time-history.recent[toRemove] > 1000 {
+		toRemove++
+	}
+
+	history.recent = history.recent[toRemove:]
+
+	history.maxKPS = bmath.MaxI(history.maxKPS, len(history.recent))
+}
+
+// GetKPS returns the number of key presses in the last second and the highest value reached
+func (history *KeyHistory) GetKPS() (int, int) {
+	return len(history.recent), history.maxKPS
+}
+
+// GetAverageDuration returns average press duration of the key in ms
+func (history *KeyHistory) GetAverageDuration(key int) float64 {
+	if history.released[key] == 0 {
+		return 0
+	}
+
+	return history.holdSum[key] / float64(history.released[key])
+}
+
+// GetBounds returns the area taken by the overlay, used to place it by HUD layout
+func (history *KeyHistory) GetBounds() (vector.Vector2d, vector.Vector2d) {
+	scale := settings.Gameplay.KeyOverlay.Scale
+
+	width := (settings.Gameplay.KeyOverlay.BarLength + laneSpacing + keyBoxWidth) * scale
+	height := (2*infoHeight + 4*laneHeight + 3*laneSpacing) * scale
+
+	top := history.Height/2 - 64
+
+	return vector.NewVec2d(history.Width-width, top), vector.NewVec2d(history.Width, top+height)
+}
+
+func (history *KeyHistory) Draw(batch *batch.QuadBatch, alpha float64) {
+	keySettings := settings.Gameplay.KeyOverlay
+
+	alpha *= keySettings.Opacity
+
+	if alpha < 0.001 || !keySettings.Show {
+		return
+	}
+
+	if history.shapeRenderer == nil {
+		history.shapeRenderer = shape.NewRenderer()
+	}
+
+	scale := keySettings.Scale
+
+	bMin, bMax := history.GetBounds()
+
+	boxLeft := bMax.X - keyBoxWidth*scale
+	barRight := boxLeft - laneSpacing*scale
+	barLeft := bMin.X
+
+	getLaneY := func(i int) float64 {
+		return bMin.Y + (infoHeight+float64(i)*(laneHeight+laneSpacing))*scale
+	}
+
+	batch.Flush()
+
+	history.shapeRenderer.SetCamera(batch.Projection)
+	history.shapeRenderer.Begin()
+
+	for i := range history.presses {
+		y1 := float32(getLaneY(i))
+		y2 := y1 + float32(laneHeight*scale)
+
+		history.shapeRenderer.SetColor(0, 0, 0, 0.4*alpha)
+		history.shapeRenderer.DrawQuad(float32(barLeft), y1, float32(barLeft), y2, float32(barRight), y2, float32(barRight), y1)
+
+		col := keyColors[i]
+
+		for _, press := range history.presses[i] {
+			x1 := math.Max(barLeft, barRight-(history.time-press.start)*keySettings.BarSpeed/1000*scale)
+			x2 := math.Max(x1+1, barRight-(history.time-press.end)*keySettings.BarSpeed/1000*scale)
+
+			if x2 <= barLeft {
+				continue
+			}
+
+			history.shapeRenderer.SetColor(float64(col.R), float64(col.G), float64(col.B), 0.8*alpha)
+			history.shapeRenderer.DrawQuad(float32(x1), y1, float32(x1), y2, float32(x2), y2, float32(x2), y1)
+		}
+
+		if history.states[i] {
+			history.shapeRenderer.SetColor(float64(col.R), float64(col.G), float64(col.B), alpha)
+		} else {
+			history.shapeRenderer.SetColor(0, 0, 0, 0.6*alpha)
+		}
+
+		history.shapeRenderer.DrawQuad(float32(boxLeft), y1, float32(boxLeft), y2, float32(bMax.X), y2, float32(bMax.X), y1)
+	}
+
+	history.shapeRenderer.End()
+
+	fnt := font.GetFont("Exo 2 Bold")
+
+	batch.ResetTransform()
+
+	for i := range history.presses {
+		y := getLaneY(i) + laneHeight/2*scale
+
+		if history.states[i] {
+			batch.SetColor(0, 0, 0, alpha)
+		} else {
+			batch.SetColor(1, 1, 1, alpha)
+		}
+
+		text := keyNames[i]
+		if history.counts[i] > 0 {
+			text = strconv.Itoa(history.counts[i])
+		}
+
+		fnt.DrawOrigin(batch, boxLeft+keyBoxWidth/2*scale, y, bmath.Origin.Centre, 14*scale, true, text)
+	}
+
+	batch.SetColor(1, 1, 1, alpha)
+
+	if keySettings.ShowKPS {
+		kps, maxKPS := history.GetKPS()
+		fnt.DrawOrigin(batch, bMax.X, bMin.Y+infoHeight/2*scale, bmath.Origin.CentreRight, 14*scale, true, fmt.Sprintf("%d KPS (max %d)", kps, maxKPS))
+	}
+
+	if keySettings.ShowDurations {
+		text := ""
+
+		for i := range keyNames {
+			if history.released[i] == 0 {
+				continue
+			}
+
+			text += fmt.Sprintf("  %s %.0fms", keyNames[i], history.GetAverageDuration(i))
+		}
+
+		if text != "" {
+			fnt.DrawOrigin(batch, bMax.X, bMax.Y-infoHeight/2*scale, bmath.Origin.CentreRight, 12*scale, true, text[2:])
+		}
+	}
+}
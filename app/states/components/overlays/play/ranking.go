@@ -19,7 +19,6 @@ import (
 	"github.com/tsunyoku/danser/framework/math/scaling"
 	"github.com/tsunyoku/danser/framework/math/vector"
 	"log"
-	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -61,9 +60,10 @@ type RankingPanel struct {
 	hpGraph        []vector.Vector2d
 	stats          []string
 	perfect        *sprite.Sprite
+	statistics     *resultStatistics
 }
 
-func NewRankingPanel(cursor *graphics.Cursor, ruleset *osu.OsuRuleSet, hitError *HitErrorMeter, hpGraph []vector.Vector2d) *RankingPanel {
+func NewRankingPanel(cursor *graphics.Cursor, ruleset *osu.OsuRuleSet, hitError *HitErrorMeter, hpGraph []vector.Vector2d, records []HitRecord) *RankingPanel {
 	panel := &RankingPanel{
 		manager:     sprite.NewSpriteManager(),
 		ScaledWidth: settings.Graphics.GetAspectRatio() * 768,
@@ -75,7 +75,7 @@ func NewRankingPanel(cursor *graphics.Cursor, ruleset *osu.OsuRuleSet, hitError
 	bg.SetColor(color.NewL(0.75))
 
 	go func() {
-		image, err := texture.NewPixmapFileString(filepath.Join(settings.General.OsuSongsDir, ruleset.GetBeatMap().Dir, ruleset.GetBeatMap().Bg))
+		image, err := ruleset.GetBeatMap().GetPixmap(ruleset.GetBeatMap().Bg)
 		if err != nil {
 			image, err = assets.GetPixmap("assets/textures/background-1.png")
 			if err != nil {
@@ -167,7 +167,7 @@ func NewRankingPanel(cursor *graphics.Cursor, ruleset *osu.OsuRuleSet, hitError
 
 	bMap := ruleset.GetBeatMap()
 
-	panel.beatmapName = fmt.Sprintf("%s - %s [%s]", bMap.Artist, bMap.Name, bMap.Difficulty)
+	panel.beatmapName = fmt.Sprintf("%s - %s [%s]", bMap.GetArtist(), bMap.GetName(), bMap.Difficulty)
 	panel.beatmapCreator = fmt.Sprintf("Beatmap by %s", bMap.Creator)
 	panel.playedBy = fmt.Sprintf("Played by %s on %s", panel.cursor.Name, panel.cursor.ScoreTime.Format("2006-01-02 15:04:05"))
 
@@ -210,6 +210,10 @@ func NewRankingPanel(cursor *graphics.Cursor, ruleset *osu.OsuRuleSet, hitError
 
 	panel.stats = strings.Split(stats, "\n")
 
+	if settings.Gameplay.ExtendedResults.Enabled {
+		panel.statistics = newResultStatistics(cursor, ruleset, hitError, records)
+	}
+
 	return panel
 }
 
@@ -239,6 +243,10 @@ func (panel *RankingPanel) Update(time float64) {
 	if panel.perfect != nil {
 		panel.perfect.Update(time)
 	}
+
+	if panel.statistics != nil {
+		panel.statistics.Update(time)
+	}
 }
 
 func (panel *RankingPanel) Draw(batch *batch.QuadBatch, alpha float64) {
@@ -353,6 +361,10 @@ func (panel *RankingPanel) Draw(batch *batch.QuadBatch, alpha float64) {
 	for i, s := range panel.stats {
 		fnt2.DrawOrigin(batch, float64(sX)+5, float64(sY)+float64(i)*12+6, bmath.Origin.TopLeft, 12, false, s)
 	}
+
+	if panel.statistics != nil {
+		panel.statistics.Draw(batch, panel.shapeRenderer, panel.ScaledWidth, alpha)
+	}
 }
 
 func getTexture(grade osu.Grade) *texture.TextureRegion {
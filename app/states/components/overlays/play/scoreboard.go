@@ -20,6 +20,7 @@ const spacing = 57.6
 const padding = 142.0
 const start = 348.0
 const visible = 6
+const boardWidth = 294.0
 
 type ScoreBoard struct {
 	scores        []*ScoreboardEntry
@@ -231,6 +232,15 @@ func (board *ScoreBoard) Update(time float64) {
 	}
 }
 
+// GetBounds returns the area taken by displayed entries, used to place the scoreboard by HUD layout
+func (board *ScoreBoard) GetBounds() (vector.Vector2d, vector.Vector2d) {
+	scale := settings.Gameplay.ScoreBoard.Scale
+
+	top := start + settings.Gameplay.ScoreBoard.YOffset - spacing/2*scale
+
+	return vector.NewVec2d(0, top), vector.NewVec2d(boardWidth*scale, top+float64(len(board.displayScores))*spacing*scale)
+}
+
 func (board *ScoreBoard) Draw(batch *batch.QuadBatch, alpha float64) {
 	if !settings.Gameplay.ScoreBoard.Show {
 		return
@@ -0,0 +1,400 @@
+package play
+
+import (
+	"fmt"
+	"github.com/tsunyoku/danser/app/beatmap/objects"
+	"github.com/tsunyoku/danser/app/bmath"
+	"github.com/tsunyoku/danser/app/graphics"
+	"github.com/tsunyoku/danser/app/oppai"
+	"github.com/tsunyoku/danser/app/rulesets/osu"
+	"github.com/tsunyoku/danser/app/settings"
+	"github.com/tsunyoku/danser/framework/graphics/batch"
+	"github.com/tsunyoku/danser/framework/graphics/font"
+	"github.com/tsunyoku/danser/framework/graphics/shape"
+	"github.com/tsunyoku/danser/framework/math/animation"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"math"
+	"strconv"
+)
+
+const (
+	statsTop      = 96.0
+	statsPadding  = 16.0
+	statsTitle    = 24.0
+	histogramBins = 41
+)
+
+// HitRecord is a single judgement kept for the results screen
+type HitRecord struct {
+	Time     float64
+	Result   osu.HitResult
+	Combo    int64
+	Accuracy float64
+}
+
+type resultSection struct {
+	difficulty float64
+	score      int64
+	hits       int64
+}
+
+// resultStatistics is the extended page of the results screen
+type resultStatistics struct {
+	fade    *animation.Glider
+	started bool
+
+	begin, end float64
+
+	histogram []float64
+	hit300    float64
+	hit100    float64
+	hit50     float64
+
+	accGraph   []vector.Vector2d
+	minAcc     float64
+	comboGraph []vector.Vector2d
+	maxCombo   float64
+	misses     []float64
+	breaks     []vector.Vector2d
+
+	sections []*resultSection
+
+	lines []string
+}
+
+func newResultStatistics(cursor *graphics.Cursor, ruleset *osu.OsuRuleSet, hitError *HitErrorMeter, records []HitRecord) *resultStatistics {
+	bMap := ruleset.GetBeatMap()
+
+	stats := &resultStatistics{
+		fade:      animation.NewGlider(0),
+		begin:     bMap.HitObjects[0].GetStartTime(),
+		end:       math.Max(bMap.HitObjects[0].GetStartTime()+1, bMap.HitObjects[len(bMap.HitObjects)-1].GetEndTime()),
+		histogram: hitError.GetHistogram(histogramBins),
+		hit300:    float64(bMap.Diff.Hit300),
+		hit100:    float64(bMap.Diff.Hit100),
+		hit50:     float64(bMap.Diff.Hit50),
+		minAcc:    100,
+		maxCombo:  1,
+	}
+
+	for _, b := range bMap.Pauses {
+		stats.breaks = append(stats.breaks, vector.NewVec2d(b.GetStartTime(), b.GetEndTime()))
+	}
+
+	stats.initSections(ruleset.GetStepStars(cursor), bMap.HitObjects, records)
+
+	for _, r := range records {
+		stats.comboGraph = append(stats.comboGraph, vector.NewVec2d(r.Time, float64(r.Combo)))
+		stats.maxCombo = math.Max(stats.maxCombo, float64(r.Combo))
+
+		if r.Result&osu.BaseHitsM == 0 {
+			continue
+		}
+
+		stats.accGraph = append(stats.accGraph, vector.NewVec2d(r.Time, r.Accuracy))
+		stats.minAcc = math.Min(stats.minAcc, r.Accuracy)
+
+		if r.Result == osu.Miss {
+			stats.misses = append(stats.misses, r.Time)
+		}
+	}
+
+	stats.minAcc = math.Max(0, math.Floor(stats.minAcc/5)*5)
+	if stats.minAcc >= 100 {
+		stats.minAcc = 95
+	}
+
+	c300, c100, c50, cMiss, _, _ := ruleset.GetHits(cursor)
+	_, maxCombo, _, _ := ruleset.GetResults(cursor)
+
+	decimals := strconv.Itoa(settings.Gameplay.PPCounter.Decimals)
+
+	stats.lines = append(stats.lines,
+		fmt.Sprintf("300: %d  100: %d  50: %d  Miss: %d", c300, c100, c50, cMiss),
+		fmt.Sprintf("Max combo: %dx", maxCombo),
+		fmt.Sprintf("Unstable Rate: %.2f", hitError.GetUnstableRateConverted()),
+		fmt.Sprintf("Error: %.2fms - %.2fms avg", hitError.GetAvgNegConverted(), hitError.GetAvgPosConverted()),
+	)
+
+	if settings.Gameplay.ExtendedResults.PP {
+		stats.lines = append(stats.lines,
+			fmt.Sprintf("Performance: %."+decimals+"fpp", ruleset.GetPP(cursor)),
+			fmt.Sprintf("If FC: %."+decimals+"fpp", ruleset.GetFCPP(cursor)),
+			fmt.Sprintf("If SS: %."+decimals+"fpp", ruleset.GetSSPP(cursor)),
+		)
+	}
+
+	return stats
+}
+
+// initSections splits the map into sections by count or length and collects peak strain and hits of each one
+func (stats *resultStatistics) initSections(stars []oppai.Stars, hitObjects []objects.IHitObject, records []HitRecord) {
+	count := bmath.MaxI(1, settings.Gameplay.ExtendedResults.SectionCount)
+
+	if length := settings.Gameplay.ExtendedResults.SectionLength * 1000; length > 0 {
+		count = bmath.ClampI(int(math.Ceil((stats.end-stats.begin)/length)), 1, 100)
+	}
+
+	stats.sections = make([]*resultSection, count)
+	for i := range stats.sections {
+		stats.sections[i] = new(resultSection)
+	}
+
+	maxDifficulty := 0.0
+
+	for i := 1; i < len(stars) && i < len(hitObjects); i++ {
+		section := stats.sections[stats.getSection(hitObjects[i].GetStartTime())]
+		section.difficulty = math.Max(section.difficulty, stars[i].AimStrain+stars[i].SpeedStrain)
+
+		maxDifficulty = math.Max(maxDifficulty, section.difficulty)
+	}
+
+	if maxDifficulty > 0 {
+		for _, s := range stats.sections {
+			s.difficulty /= maxDifficulty
+		}
+	}
+
+	for _, r := range records {
+		if r.Result&osu.BaseHitsM == 0 {
+			continue
+		}
+
+		section := stats.sections[stats.getSection(r.Time)]
+		section.score += r.Result.ScoreValue()
+		section.hits++
+	}
+}
+
+func (stats *resultStatistics) getSection(time float64) int {
+	return bmath.ClampI(int((time-stats.begin)/(stats.end-stats.begin)*float64(len(stats.sections))), 0, len(stats.sections)-1)
+}
+
+func (stats *resultStatistics) Update(time float64) {
+	if !stats.started {
+		stats.started = true
+
+		showTime := time + settings.Gameplay.ExtendedResults.ShowAfter*1000
+		stats.fade.AddEventS(showTime, showTime+500, 0, 1)
+	}
+
+	stats.fade.Update(time)
+}
+
+func (stats *resultStatistics) Draw(batch *batch.QuadBatch, renderer *shape.Renderer, width, alpha float64) {
+	alpha *= stats.fade.GetValue()
+
+	if alpha < 0.001 {
+		return
+	}
+
+	config := settings.Gameplay.ExtendedResults
+
+	columnWidth := (width - 3*statsPadding) / 2
+	rowHeight := (768 - statsTop - 4*statsPadding) / 3
+
+	cell := func(row, column int, span float64) (vector.Vector2d, vector.Vector2d) {
+		position := vector.NewVec2d(statsPadding+float64(column)*(columnWidth+statsPadding), statsTop+statsPadding+float64(row)*(rowHeight+statsPadding))
+		return position, vector.NewVec2d(columnWidth*span+statsPadding*(span-1), rowHeight)
+	}
+
+	type chart struct {
+		enabled  bool
+		title    string
+		position vector.Vector2d
+		size     vector.Vector2d
+		draw     func(renderer *shape.Renderer, position, size vector.Vector2d, alpha float64)
+	}
+
+	charts := []*chart{
+		{enabled: config.HitErrors, title: "Hit errors", draw: stats.drawHistogram},
+		{enabled: true, title: "Statistics"},
+		{enabled: config.Accuracy, title: "Accuracy", draw: stats.drawAccuracy},
+		{enabled: config.Combo, title: "Combo", draw: stats.drawCombo},
+		{enabled: config.Sections, title: "Difficulty / accuracy per section", draw: stats.drawSections},
+	}
+
+	for i, c := range charts {
+		span := 1.0
+		if i == len(charts)-1 {
+			span = 2
+		}
+
+		c.position, c.size = cell(i/2, i%2, span)
+	}
+
+	batch.Flush()
+
+	renderer.SetCamera(batch.Projection)
+	renderer.Begin()
+
+	renderer.SetColor(0, 0, 0, 0.85*alpha)
+	renderer.DrawQuad(0, statsTop, 0, 768, float32(width), 768, float32(width), statsTop)
+
+	for _, c := range charts {
+		if !c.enabled {
+			continue
+		}
+
+		x1, y1 := c.position.X32(), c.position.Y32()
+		x2, y2 := x1+c.size.X32(), y1+c.size.Y32()
+
+		renderer.SetColor(1, 1, 1, 0.05*alpha)
+		renderer.DrawQuad(x1, y1, x1, y2, x2, y2, x2, y1)
+
+		if c.draw != nil {
+			c.draw(renderer, c.position.AddS(8, statsTitle), c.size.SubS(16, statsTitle+8), alpha)
+		}
+	}
+
+	renderer.End()
+
+	fnt := font.GetFont("Ubuntu Regular")
+
+	batch.ResetTransform()
+	batch.SetColor(1, 1, 1, alpha)
+
+	for _, c := range charts {
+		if c.enabled {
+			fnt.DrawOrigin(batch, c.position.X+8, c.position.Y+4, bmath.Origin.TopLeft, 16, false, c.title)
+		}
+	}
+
+	for i, line := range stats.lines {
+		fnt.DrawOrigin(batch, charts[1].position.X+8, charts[1].position.Y+statsTitle+4+float64(i)*22, bmath.Origin.TopLeft, 18, false, line)
+	}
+
+	if config.Sections {
+		position, size := charts[4].position.AddS(8, statsTitle), charts[4].size.SubS(16, statsTitle+8)
+		sWidth := size.X / float64(len(stats.sections))
+
+		for i, s := range stats.sections {
+			if s.hits == 0 {
+				continue
+			}
+
+			fnt.DrawOrigin(batch, position.X+(float64(i)+0.5)*sWidth, position.Y+size.Y, bmath.Origin.BottomCentre, 12, false, fmt.Sprintf("%.1f%%", s.getAccuracy()))
+		}
+	}
+}
+
+func (stats *resultStatistics) drawHistogram(renderer *shape.Renderer, position, size vector.Vector2d, alpha float64) {
+	binWidth := size.X / histogramBins
+	bottom := position.Y32() + size.Y32()
+
+	for i, value := range stats.histogram {
+		offset := math.Abs((float64(i)+0.5)/histogramBins*2-1) * stats.hit50
+
+		col := colors[2]
+		if offset < stats.hit300 {
+			col = colors[0]
+		} else if offset < stats.hit100 {
+			col = colors[1]
+		}
+
+		renderer.SetColor(float64(col.R), float64(col.G), float64(col.B), alpha)
+
+		x1 := float32(position.X + float64(i)*binWidth + 1)
+		x2 := float32(position.X + float64(i+1)*binWidth - 1)
+		top := bottom - float32(value*size.Y)
+
+		renderer.DrawQuad(x1, top, x1, bottom, x2, bottom, x2, top)
+	}
+
+	centre := float32(position.X + size.X/2)
+
+	renderer.SetColor(1, 1, 1, alpha)
+	renderer.DrawLine(centre, position.Y32(), centre, bottom, 2)
+}
+
+func (stats *resultStatistics) drawAccuracy(renderer *shape.Renderer, position, size vector.Vector2d, alpha float64) {
+	renderer.SetColor(1, 0.8, 0.4, alpha)
+
+	stats.drawGraph(renderer, stats.accGraph, position, size, stats.minAcc, 100, false)
+}
+
+func (stats *resultStatistics) drawCombo(renderer *shape.Renderer, position, size vector.Vector2d, alpha float64) {
+	renderer.SetColor(1, 1, 1, 0.15*alpha)
+
+	for _, b := range stats.breaks {
+		x1, x2 := stats.getX(b.X, position, size), stats.getX(b.Y, position, size)
+		renderer.DrawQuad(x1, position.Y32(), x1, position.Y32()+size.Y32(), x2, position.Y32()+size.Y32(), x2, position.Y32())
+	}
+
+	renderer.SetColor(1, 0.2, 0.2, alpha)
+
+	for _, m := range stats.misses {
+		x := stats.getX(m, position, size)
+		renderer.DrawLine(x, position.Y32(), x, position.Y32()+size.Y32(), 1)
+	}
+
+	renderer.SetColor(0.4, 0.7, 1, alpha)
+
+	stats.drawGraph(renderer, stats.comboGraph, position, size, 0, stats.maxCombo, true)
+}
+
+func (stats *resultStatistics) drawSections(renderer *shape.Renderer, position, size vector.Vector2d, alpha float64) {
+	sWidth := size.X / float64(len(stats.sections))
+	bottom := position.Y32() + size.Y32() - 16
+	height := size.Y - 16
+
+	for i, s := range stats.sections {
+		x := position.X + float64(i)*sWidth
+
+		x1, x2, x3 := float32(x+sWidth*0.15), float32(x+sWidth*0.5), float32(x+sWidth*0.85)
+
+		dTop := bottom - float32(s.difficulty*height)
+
+		renderer.SetColor(0.6, 0.6, 0.8, alpha)
+		renderer.DrawQuad(x1, dTop, x1, bottom, x2-1, bottom, x2-1, dTop)
+
+		if s.hits == 0 {
+			continue
+		}
+
+		accuracy := s.getAccuracy() / 100
+		aTop := bottom - float32(accuracy*height)
+
+		// Green for 100% down to red for 80% and lower
+		health := bmath.ClampF64((accuracy-0.8)/0.2, 0, 1)
+
+		renderer.SetColor(1-health*0.7, 0.3+health*0.7, 0.3, alpha)
+		renderer.DrawQuad(x2+1, aTop, x2+1, bottom, x3, bottom, x3, aTop)
+	}
+}
+
+// drawGraph draws a line through points scaled to the area, stepped graphs keep the value until the next point
+func (stats *resultStatistics) drawGraph(renderer *shape.Renderer, points []vector.Vector2d, position, size vector.Vector2d, min, max float64, stepped bool) {
+	getY := func(value float64) float32 {
+		return float32(position.Y + size.Y*(1-bmath.ClampF64((value-min)/(max-min), 0, 1)))
+	}
+
+	for i := 0; i < len(points)-1; i++ {
+		x1, y1 := stats.getX(points[i].X, position, size), getY(points[i].Y)
+		x2, y2 := stats.getX(points[i+1].X, position, size), getY(points[i+1].Y)
+
+		if stepped {
+			drawSegment(renderer, x1, y1, x2, y1)
+			drawSegment(renderer, x2, y1, x2, y2)
+		} else {
+			drawSegment(renderer, x1, y1, x2, y2)
+		}
+	}
+}
+
+func (stats *resultStatistics) getX(time float64, position, size vector.Vector2d) float32 {
+	return float32(position.X + size.X*bmath.ClampF64((time-stats.begin)/(stats.end-stats.begin), 0, 1))
+}
+
+// drawSegment skips zero length lines as they can't be drawn
+func drawSegment(renderer *shape.Renderer, x1, y1, x2, y2 float32) {
+	if x1 == x2 && y1 == y2 {
+		return
+	}
+
+	renderer.DrawLine(x1, y1, x2, y2, 2)
+}
+
+func (section *resultSection) getAccuracy() float64 {
+	return 100 * float64(section.score) / float64(section.hits*300)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/tsunyoku/danser/app/discord"
 	"github.com/tsunyoku/danser/app/graphics"
 	"github.com/tsunyoku/danser/app/input"
+	"github.com/tsunyoku/danser/app/oppai"
 	"github.com/tsunyoku/danser/app/rulesets/osu"
 	"github.com/tsunyoku/danser/app/settings"
 	"github.com/tsunyoku/danser/app/skin"
@@ -30,6 +31,7 @@ import (
 	color2 "github.com/tsunyoku/danser/framework/math/color"
 	"github.com/tsunyoku/danser/framework/math/vector"
 	"math"
+	"math/rand"
 	"strconv"
 	"strings"
 )
@@ -78,6 +80,7 @@ type ScoreOverlay struct {
 	lastPresses [4]float64
 	keyOverlay  *sprite.SpriteManager
 	keys        []*sprite.Sprite
+	keyHistory  *play.KeyHistory
 
 	ScaledWidth  float64
 	ScaledHeight float64
@@ -122,8 +125,17 @@ type ScoreOverlay struct {
 
 	arrows *sprite.SpriteManager
 
+	comboBursts *sprite.SpriteManager
+	burstFrames []*texture.TextureRegion
+	burstSound  *bass.Sample
+	burstIndex  int
+	burstRandom *rand.Rand
+
+	countdown *sprite.SpriteManager
+
 	resultsFade *animation.Glider
 	hpSections  []vector.Vector2d
+	hitRecords  []play.HitRecord
 	panel       *play.RankingPanel
 	created     bool
 	skipTo      float64
@@ -132,6 +144,16 @@ type ScoreOverlay struct {
 	beatmapEnd    float64
 
 	circularMetre *texture.TextureRegion
+
+	hudComponents map[string]*settings.HUDComponent
+	hudFades      map[string]*hudFade
+	modsWidth     float64
+
+	stepStars   []oppai.Stars
+	strains     []vector.Vector2d
+	strainStart float64
+	strainEnd   float64
+	maxPPGlider *animation.TargetGlider
 }
 
 func loadFonts() {
@@ -174,10 +196,10 @@ func NewScoreOverlay(ruleset *osu.OsuRuleSet, cursor *graphics.Cursor) *ScoreOve
 
 	overlay.bgDim = animation.NewGlider(1)
 
-	overlay.combobreak = audio.LoadSample("combobreak")
+	overlay.combobreak = audio.LoadUISample("combobreak")
 
-	audio.LoadSample("sectionpass")
-	audio.LoadSample("sectionfail")
+	audio.LoadUISample("sectionpass")
+	audio.LoadUISample("sectionfail")
 
 	overlay.sPass = sprite.NewSpriteSingle(skin.GetTexture("section-pass"), 0, vector.NewVec2d(overlay.ScaledWidth, overlay.ScaledHeight).Scl(0.5), bmath.Origin.Centre)
 	overlay.sPass.SetAlpha(0)
@@ -229,6 +251,8 @@ func NewScoreOverlay(ruleset *osu.OsuRuleSet, cursor *graphics.Cursor) *ScoreOve
 		overlay.keyOverlay.Add(key)
 	}
 
+	overlay.keyHistory = play.NewKeyHistory(cursor, overlay.ScaledWidth, overlay.ScaledHeight)
+
 	overlay.hitErrorMeter = play.NewHitErrorMeter(overlay.ScaledWidth, overlay.ScaledHeight, ruleset.GetBeatMap().Diff)
 
 	showAfterSkip := 2000.0
@@ -269,6 +293,9 @@ func NewScoreOverlay(ruleset *osu.OsuRuleSet, cursor *graphics.Cursor) *ScoreOve
 	overlay.entry.AddPlayer(overlay.cursor.Name)
 
 	overlay.initArrows()
+	overlay.initComboBursts()
+	overlay.initCountdown()
+	overlay.initWidgets()
 
 	return overlay
 }
@@ -301,6 +328,8 @@ func (overlay *ScoreOverlay) hitReceived(_ *graphics.Cursor, time int64, number
 		overlay.combo = overlay.newCombo
 		overlay.newCombo++
 		overlay.nextEnd = overlay.normalTime + 300
+
+		overlay.checkComboBurst(overlay.newCombo)
 	} else if comboResult == osu.ComboResults.Reset {
 		if overlay.newCombo > 20 && overlay.combobreak != nil && !overlay.audioDisabled {
 			overlay.combobreak.Play()
@@ -319,8 +348,10 @@ func (overlay *ScoreOverlay) hitReceived(_ *graphics.Cursor, time int64, number
 	overlay.scoreGlider.SetTarget(float64(score))
 	overlay.accuracyGlider.SetTarget(accuracy)
 	overlay.ppGlider.SetTarget(pp)
+	overlay.maxPPGlider.SetTarget(overlay.ruleset.GetMaxPP(overlay.cursor))
 
 	overlay.hpSections = append(overlay.hpSections, vector.NewVec2d(float64(time), overlay.ruleset.GetHP(overlay.cursor)))
+	overlay.hitRecords = append(overlay.hitRecords, play.HitRecord{Time: float64(time), Result: result, Combo: overlay.newCombo, Accuracy: accuracy})
 
 	if overlay.oldGrade != grade {
 		go func() {
@@ -383,6 +414,7 @@ func (overlay *ScoreOverlay) Update(time float64) {
 	overlay.rankBack.Update(overlay.audioTime)
 	overlay.rankFront.Update(overlay.audioTime)
 	overlay.arrows.Update(overlay.audioTime)
+	overlay.countdown.Update(overlay.audioTime)
 
 	//normal timing
 	overlay.updateNormal(overlay.normalTime)
@@ -390,6 +422,7 @@ func (overlay *ScoreOverlay) Update(time float64) {
 
 func (overlay *ScoreOverlay) updateNormal(time float64) {
 	overlay.updateBreaks(time)
+	overlay.updateHUDVisibility(time)
 
 	if overlay.panel != nil {
 		overlay.panel.Update(time)
@@ -398,7 +431,7 @@ func (overlay *ScoreOverlay) updateNormal(time float64) {
 		cTime := overlay.normalTime
 
 		go func() {
-			overlay.panel = play.NewRankingPanel(overlay.cursor, overlay.ruleset, overlay.hitErrorMeter, overlay.hpSections)
+			overlay.panel = play.NewRankingPanel(overlay.cursor, overlay.ruleset, overlay.hitErrorMeter, overlay.hpSections, overlay.hitRecords)
 
 			s := cTime
 
@@ -426,6 +459,7 @@ func (overlay *ScoreOverlay) updateNormal(time float64) {
 	}
 
 	overlay.mods.Update(time)
+	overlay.comboBursts.Update(time)
 
 	overlay.newComboScale.Update(time)
 	overlay.newComboScaleB.Update(time)
@@ -457,6 +491,9 @@ func (overlay *ScoreOverlay) updateNormal(time float64) {
 	overlay.ppGlider.SetDecimals(settings.Gameplay.PPCounter.Decimals)
 	overlay.ppGlider.Update(time)
 
+	overlay.maxPPGlider.SetDecimals(settings.Gameplay.PPCounter.Decimals)
+	overlay.maxPPGlider.Update(time)
+
 	currentStates := [4]bool{overlay.cursor.LeftKey, overlay.cursor.RightKey, overlay.cursor.LeftMouse && !overlay.cursor.LeftKey, overlay.cursor.RightMouse && !overlay.cursor.RightKey}
 
 	for i, state := range currentStates {
@@ -491,6 +528,7 @@ func (overlay *ScoreOverlay) updateNormal(time float64) {
 	}
 
 	overlay.keyOverlay.Update(time)
+	overlay.keyHistory.Update(time)
 	overlay.bgDim.Update(time)
 
 	overlay.resultsFade.Update(time)
@@ -535,6 +573,14 @@ func (overlay *ScoreOverlay) SetMusic(music *bass.Track) {
 
 func (overlay *ScoreOverlay) DrawBeforeObjects(batch *batch.QuadBatch, _ []color2.Color, alpha float64) {
 	overlay.boundaries.Draw(batch.Projection, float32(overlay.ruleset.GetBeatMap().Diff.CircleRadius), float32(alpha*overlay.bgDim.GetValue()))
+
+	prev := batch.Projection
+	batch.SetCamera(overlay.camera.GetProjectionView())
+	batch.SetColor(1, 1, 1, alpha)
+
+	overlay.comboBursts.Draw(overlay.lastTime, batch)
+
+	batch.SetCamera(prev)
 }
 
 func (overlay *ScoreOverlay) DrawNormal(batch *batch.QuadBatch, _ []color2.Color, alpha float64) {
@@ -553,11 +599,18 @@ func (overlay *ScoreOverlay) DrawNormal(batch *batch.QuadBatch, _ []color2.Color
 	prev := batch.Projection
 	batch.SetCamera(overlay.camera.GetProjectionView())
 
-	overlay.hitErrorMeter.Draw(batch, alpha)
+	errorMin, errorMax := overlay.hitErrorMeter.GetBounds()
+	errorAlpha := overlay.beginHUDComponent(batch, "HitError", hudBox{errorMin, errorMax})
+
+	overlay.hitErrorMeter.Draw(batch, alpha*errorAlpha)
+
+	overlay.endHUDComponent(batch)
 
 	batch.SetScale(1, 1)
 	batch.SetColor(1, 1, 1, alpha)
 
+	overlay.countdown.Draw(overlay.audioTime, batch)
+
 	if overlay.skip != nil {
 		overlay.skip.Draw(overlay.lastTime, batch)
 	}
@@ -571,20 +624,42 @@ func (overlay *ScoreOverlay) DrawHUD(batch *batch.QuadBatch, _ []color2.Color, a
 	batch.ResetTransform()
 	batch.SetColor(1, 1, 1, alpha)
 
-	overlay.entry.Draw(batch, alpha)
+	boardMin, boardMax := overlay.entry.GetBounds()
+	boardAlpha := overlay.beginHUDComponent(batch, "ScoreBoard", hudBox{boardMin, boardMax})
+
+	overlay.entry.Draw(batch, alpha*boardAlpha)
+
+	overlay.endHUDComponent(batch)
+
+	batch.ResetTransform()
+	batch.SetColor(1, 1, 1, alpha)
 
 	overlay.passContainer.Draw(overlay.audioTime, batch)
 
 	overlay.drawScore(batch, alpha)
 	overlay.drawCombo(batch, alpha)
-	overlay.hpBar.Draw(batch, alpha)
+
+	hpAlpha := overlay.beginHUDComponent(batch, "HpBar", newHUDBox(vector.NewVec2d(0, 0), overlay.hpBar.GetSize().X, overlay.hpBar.GetSize().Y, bmath.Origin.TopLeft))
+
+	overlay.hpBar.Draw(batch, alpha*hpAlpha)
+
+	overlay.endHUDComponent(batch)
+
 	overlay.drawKeys(batch, alpha)
 
 	batch.ResetTransform()
 	batch.SetColor(1, 1, 1, alpha)
 
 	if settings.Gameplay.Mods.Show {
+		modsScale := settings.Gameplay.Mods.Scale
+		modsAlpha := overlay.beginHUDComponent(batch, "Mods", newHUDBox(vector.NewVec2d(overlay.ScaledWidth, 150), overlay.modsWidth, 64*modsScale, bmath.Origin.CentreRight))
+
+		batch.SetColor(1, 1, 1, alpha*modsAlpha)
 		overlay.mods.Draw(overlay.lastTime, batch)
+
+		overlay.endHUDComponent(batch)
+
+		batch.SetColor(1, 1, 1, alpha)
 	}
 
 	if settings.Gameplay.ShowWarningArrows {
@@ -592,6 +667,7 @@ func (overlay *ScoreOverlay) DrawHUD(batch *batch.QuadBatch, _ []color2.Color, a
 	}
 
 	overlay.drawPP(batch, alpha)
+	overlay.drawWidgets(batch, alpha)
 
 	if overlay.panel != nil {
 		overlay.panel.Draw(batch, overlay.resultsFade.GetValue())
@@ -626,21 +702,25 @@ func (overlay *ScoreOverlay) drawScore(batch *batch.QuadBatch, alpha float64) {
 	overlay.shapeRenderer.SetCamera(overlay.camera.GetProjectionView())
 
 	if settings.Gameplay.Score.ProgressBar == "Pie" {
+		pieAlpha := scoreAlpha * overlay.beginHUDComponent(batch, "Progress", newHUDBox(vector.NewVec2d(accOffset, accYPos+accSize/2), 32*scoreScale, 32*scoreScale, bmath.Origin.Centre))
+
 		if progress < 0.0 {
-			overlay.shapeRenderer.SetColor(0.4, 0.8, 0.4, 0.6*scoreAlpha)
+			overlay.shapeRenderer.SetColor(0.4, 0.8, 0.4, 0.6*pieAlpha)
 		} else {
-			overlay.shapeRenderer.SetColor(1, 1, 1, 0.6*scoreAlpha)
+			overlay.shapeRenderer.SetColor(1, 1, 1, 0.6*pieAlpha)
 		}
 
 		overlay.shapeRenderer.Begin()
 		overlay.shapeRenderer.DrawCircleProgressS(vector.NewVec2f(float32(accOffset), float32(accYPos+accSize/2)), 16*float32(settings.Gameplay.Score.Scale), 40, float32(progress))
 		overlay.shapeRenderer.End()
 
-		batch.SetColor(1, 1, 1, scoreAlpha)
+		batch.SetColor(1, 1, 1, pieAlpha)
 		batch.SetScale(scoreScale, scoreScale)
 		batch.SetTranslation(vector.NewVec2d(accOffset, accYPos+accSize/2))
 		batch.DrawTexture(*overlay.circularMetre)
 
+		overlay.endHUDComponent(batch)
+
 		accOffset -= 44.8 * scoreScale
 	} else if progress > 0.0 {
 		thickness := barThickness * scoreScale
@@ -665,21 +745,33 @@ func (overlay *ScoreOverlay) drawScore(batch *batch.QuadBatch, alpha float64) {
 
 		positionY += thickness / 2
 
-		overlay.shapeRenderer.SetColor(1, 1, 0.5, 0.5*scoreAlpha)
+		barAlpha := scoreAlpha * overlay.beginHUDComponent(batch, "Progress", newHUDBox(vector.NewVec2d(positionX, positionY), bWidth, thickness, bmath.Origin.CentreLeft))
+
+		overlay.shapeRenderer.SetColor(1, 1, 0.5, 0.5*barAlpha)
 
 		overlay.shapeRenderer.Begin()
 		overlay.shapeRenderer.SetAdditive(true)
 		overlay.shapeRenderer.DrawLine(float32(positionX), float32(positionY), float32(positionX+progress*bWidth), float32(positionY), float32(thickness))
 		overlay.shapeRenderer.SetAdditive(false)
 		overlay.shapeRenderer.End()
+
+		overlay.endHUDComponent(batch)
 	}
 
 	batch.ResetTransform()
-	batch.SetColor(1, 1, 1, scoreAlpha)
 
 	scoreText := fmt.Sprintf("%08d", int64(math.Round(overlay.scoreGlider.GetValue())))
+	scoreWidth := overlay.scoreFont.GetWidthMonospaced(scoreSize, scoreText)
+
+	batch.SetColor(1, 1, 1, scoreAlpha*overlay.beginHUDComponent(batch, "Score", newHUDBox(vector.NewVec2d(overlay.ScaledWidth+rightOffset, 0), scoreWidth, scoreSize, bmath.Origin.TopRight)))
 	overlay.scoreFont.DrawOrigin(batch, overlay.ScaledWidth+rightOffset+scoreOverlap, 0, bmath.Origin.TopRight, scoreSize, true, scoreText)
 
+	overlay.endHUDComponent(batch)
+
+	accLeft := accOffset - 16*scoreScale
+
+	batch.SetColor(1, 1, 1, scoreAlpha*overlay.beginHUDComponent(batch, "Accuracy", newHUDBox(vector.NewVec2d(overlay.ScaledWidth+rightOffset, accYPos), overlay.ScaledWidth+rightOffset-accLeft, accSize, bmath.Origin.TopRight)))
+
 	accText := fmt.Sprintf("%5.2f%%", overlay.accuracyGlider.GetValue())
 	overlay.scoreFont.DrawOrigin(batch, overlay.ScaledWidth+rightOffset+accOverlap, accYPos, bmath.Origin.TopRight, accSize, true, accText)
 
@@ -693,6 +785,8 @@ func (overlay *ScoreOverlay) drawScore(batch *batch.QuadBatch, alpha float64) {
 	} else if overlay.rankBack.Textures[0] != nil {
 		batch.DrawTexture(*overlay.rankBack.Textures[0])
 	}
+
+	overlay.endHUDComponent(batch)
 }
 
 func (overlay *ScoreOverlay) drawCombo(batch *batch.QuadBatch, alpha float64) {
@@ -704,6 +798,9 @@ func (overlay *ScoreOverlay) drawCombo(batch *batch.QuadBatch, alpha float64) {
 
 	cmbSize := overlay.comboFont.GetSize() * settings.Gameplay.ComboCounter.Scale
 
+	comboWidth := overlay.comboFont.GetWidth(cmbSize, fmt.Sprintf("%dx", overlay.combo))
+	comboAlpha *= overlay.beginHUDComponent(batch, "Combo", newHUDBox(vector.NewVec2d(0, overlay.ScaledHeight), comboWidth, cmbSize, bmath.Origin.BottomLeft))
+
 	posX := overlay.comboSlide.GetValue()*overlay.comboFont.GetWidth(cmbSize*overlay.newComboScale.GetValue(), fmt.Sprintf("%dx", overlay.combo)) + 2.5
 	posY := overlay.ScaledHeight - 12.8
 	origY := overlay.comboFont.GetSize()*0.375 - 9
@@ -719,6 +816,8 @@ func (overlay *ScoreOverlay) drawCombo(batch *batch.QuadBatch, alpha float64) {
 
 	batch.SetColor(1, 1, 1, comboAlpha)
 	overlay.comboFont.DrawOrigin(batch, posX, posY+origY*overlay.newComboScale.GetValue()*settings.Gameplay.ComboCounter.Scale, bmath.Origin.BottomLeft, cmbSize*overlay.newComboScale.GetValue(), false, fmt.Sprintf("%dx", overlay.combo))
+
+	overlay.endHUDComponent(batch)
 }
 
 func (overlay *ScoreOverlay) drawPP(batch *batch.QuadBatch, alpha float64) {
@@ -737,10 +836,15 @@ func (overlay *ScoreOverlay) drawPP(batch *batch.QuadBatch, alpha float64) {
 	position := vector.NewVec2d(settings.Gameplay.PPCounter.XPosition, settings.Gameplay.PPCounter.YPosition)
 	origin := storyboard.Origin[settings.Gameplay.PPCounter.Align]
 
+	ppWidth := overlay.ppFont.GetWidthMonospaced(40*ppScale, ppText)
+	ppAlpha *= overlay.beginHUDComponent(batch, "PP", newHUDBox(position, ppWidth, 40*ppScale, origin))
+
 	batch.SetColor(0, 0, 0, ppAlpha*0.8)
 	overlay.ppFont.DrawOriginV(batch, position.AddS(ppScale, ppScale), origin, 40*ppScale, true, ppText)
 	batch.SetColor(1, 1, 1, ppAlpha)
 	overlay.ppFont.DrawOriginV(batch, position, origin, 40*ppScale, true, ppText)
+
+	overlay.endHUDComponent(batch)
 }
 
 func (overlay *ScoreOverlay) drawKeys(batch *batch.QuadBatch, alpha float64) {
@@ -752,14 +856,27 @@ func (overlay *ScoreOverlay) drawKeys(batch *batch.QuadBatch, alpha float64) {
 
 	batch.ResetTransform()
 
+	if settings.Gameplay.KeyOverlay.Style == "Bars" {
+		historyMin, historyMax := overlay.keyHistory.GetBounds()
+		historyAlpha := overlay.beginHUDComponent(batch, "KeyOverlay", hudBox{historyMin, historyMax})
+
+		overlay.keyHistory.Draw(batch, alpha*historyAlpha)
+
+		overlay.endHUDComponent(batch)
+
+		return
+	}
+
 	keyScale := settings.Gameplay.KeyOverlay.Scale
 
+	keyAlpha *= overlay.beginHUDComponent(batch, "KeyOverlay", newHUDBox(vector.NewVec2d(overlay.ScaledWidth, overlay.ScaledHeight/2-64), 48*keyScale, 200*keyScale, bmath.Origin.TopRight))
+
 	batch.SetColor(1, 1, 1, keyAlpha)
 	batch.SetScale(keyScale, keyScale)
 
 	overlay.keyOverlay.Draw(overlay.lastTime, batch)
 
-	col := skin.GetInfo().InputOverlayText
+	col := skin.GetInfoFor("inputoverlay-key").InputOverlayText
 	batch.SetColor(float64(col.R), float64(col.G), float64(col.B), keyAlpha)
 
 	for i := 0; i < 4; i++ {
@@ -786,6 +903,8 @@ func (overlay *ScoreOverlay) drawKeys(batch *batch.QuadBatch, alpha float64) {
 			overlay.scoreEFont.DrawOrigin(batch, posX, posY, bmath.Origin.Centre, scale * overlay.scoreEFont.GetSize(), false, text)
 		}
 	}
+
+	overlay.endHUDComponent(batch)
 }
 
 func (overlay *ScoreOverlay) getProgress() float64 {
@@ -892,6 +1011,8 @@ func (overlay *ScoreOverlay) initMods() {
 
 		overlay.mods.Add(mod)
 	}
+
+	overlay.modsWidth = -offset
 }
 
 func (overlay *ScoreOverlay) initArrows() {
@@ -961,4 +1082,154 @@ func (overlay *ScoreOverlay) SetBeatmapEnd(end float64) {
 
 func (overlay *ScoreOverlay) ShouldDrawHUDBeforeCursor() bool {
 	return true
-}
\ No newline at end of file
+}
+
+func (overlay *ScoreOverlay) initComboBursts() {
+	overlay.comboBursts = sprite.NewSpriteManager()
+	overlay.burstFrames = skin.GetFrames("comboburst", true)
+	overlay.burstSound = audio.LoadUISample("comboburst")
+	overlay.burstRandom = rand.New(rand.NewSource(settings.Dance.GetSeed(overlay.ruleset.GetBeatMap().MD5)))
+}
+
+// isBurstCombo reports whether combo is one of osu!stable's combo burst milestones
+func isBurstCombo(combo int64) bool {
+	return combo == 30 || combo == 60 || (combo >= 100 && combo%50 == 0)
+}
+
+func (overlay *ScoreOverlay) checkComboBurst(combo int64) {
+	if isBurstCombo(combo) && settings.Gameplay.ShowComboBursts && len(overlay.burstFrames) > 0 {
+		overlay.showComboBurst(overlay.normalTime)
+	}
+
+	if !settings.Gameplay.ShowComboBursts || overlay.burstSound == nil || overlay.audioDisabled {
+		return
+	}
+
+	// CustomComboBurstSounds replaces milestones at which the sound is played
+	playSound := isBurstCombo(combo)
+
+	if sounds := skin.GetInfo().CustomComboBurstSounds; len(sounds) > 0 {
+		playSound = false
+
+		for _, c := range sounds {
+			if int64(c) == combo {
+				playSound = true
+				break
+			}
+		}
+	}
+
+	if playSound {
+		overlay.burstSound.Play()
+	}
+}
+
+func (overlay *ScoreOverlay) showComboBurst(time float64) {
+	var tex *texture.TextureRegion
+
+	if skin.GetInfo().ComboBurstRandom {
+		tex = overlay.burstFrames[overlay.burstRandom.Intn(len(overlay.burstFrames))]
+	} else {
+		tex = overlay.burstFrames[overlay.burstIndex%len(overlay.burstFrames)]
+		overlay.burstIndex++
+	}
+
+	// Burst comes from the side opposite to the cursor so it doesn't cover the play area the player is looking at
+	fromRight := overlay.cursor.Position.X < 256
+
+	width := float64(tex.Width)
+
+	startX, endX := -width, 0.0
+	origin := bmath.Origin.BottomLeft
+
+	if fromRight {
+		startX, endX = overlay.ScaledWidth+width, overlay.ScaledWidth
+		origin = bmath.Origin.BottomRight
+	}
+
+	burst := sprite.NewSpriteSingle(tex, time, vector.NewVec2d(startX, overlay.ScaledHeight), origin)
+	burst.SetHFlip(fromRight)
+	burst.AddTransform(animation.NewSingleTransform(animation.MoveX, easing.OutQuad, time, time+700, startX, endX))
+	burst.AddTransform(animation.NewSingleTransform(animation.Fade, easing.Linear, time, time+700, 1, 1))
+	burst.AddTransform(animation.NewSingleTransform(animation.Fade, easing.Linear, time+1400, time+2000, 1, 0))
+	burst.ResetValuesToTransforms()
+	burst.AdjustTimesToTransformations()
+	burst.ShowForever(false)
+
+	overlay.comboBursts.Add(burst)
+}
+
+func (overlay *ScoreOverlay) initCountdown() {
+	overlay.countdown = sprite.NewSpriteManager()
+
+	bMap := overlay.ruleset.GetBeatMap()
+
+	if bMap.Countdown == 0 || len(bMap.HitObjects) == 0 || len(bMap.Timings.Points) == 0 {
+		return
+	}
+
+	firstTime := bMap.HitObjects[0].GetStartTime()
+	point := bMap.Timings.GetOriginalPoint(firstTime)
+
+	beatLen := point.BaseBpm
+
+	switch bMap.Countdown {
+	case 2:
+		beatLen *= 2
+	case 3:
+		beatLen /= 2
+	}
+
+	if beatLen <= 0 {
+		return
+	}
+
+	// "Go" lands on the last beat before the first object, CountdownOffset shifts it back by whole beats
+	goTime := point.Time + (math.Ceil((firstTime-point.Time)/beatLen)-1-float64(bMap.CountdownOffset))*beatLen
+	readyTime := goTime - 5*beatLen
+
+	if readyTime < 0 {
+		return
+	}
+
+	center := vector.NewVec2d(overlay.ScaledWidth, overlay.ScaledHeight).Scl(0.5)
+
+	addSample := func(name string, time float64) {
+		aSprite := audio.NewAudioSprite(audio.LoadUISample(name), time)
+		aSprite.SetCondition(func(_ float64) bool {
+			return !overlay.audioDisabled
+		})
+
+		overlay.countdown.Add(aSprite)
+	}
+
+	addSprite := func(name string, start, end float64) {
+		tex := skin.GetTexture(name)
+		if tex == nil {
+			return
+		}
+
+		sp := sprite.NewSpriteSingle(tex, start, center, bmath.Origin.Centre)
+		sp.AddTransform(animation.NewSingleTransform(animation.Fade, easing.Linear, start, start+beatLen/4, 0, 1))
+		sp.AddTransform(animation.NewSingleTransform(animation.Scale, easing.OutQuad, start, start+beatLen/2, 1.2, 1))
+		sp.AddTransform(animation.NewSingleTransform(animation.Fade, easing.Linear, end-beatLen/4, end, 1, 0))
+		sp.ResetValuesToTransforms()
+		sp.AdjustTimesToTransformations()
+		sp.ShowForever(false)
+
+		overlay.countdown.Add(sp)
+	}
+
+	addSprite("ready", readyTime, goTime-3*beatLen)
+	addSample("readys", readyTime)
+
+	for i := 3; i >= 1; i-- {
+		time := goTime - float64(i)*beatLen
+
+		addSprite("count"+strconv.Itoa(i), time, time+beatLen)
+		addSample("count"+strconv.Itoa(i)+"s", time)
+	}
+
+	addSprite("go", goTime, goTime+beatLen)
+	addSample("gos", goTime)
+}
@@ -0,0 +1,247 @@
+package overlays
+
+import (
+	"fmt"
+	"github.com/tsunyoku/danser/app/bmath"
+	"github.com/tsunyoku/danser/app/settings"
+	"github.com/tsunyoku/danser/app/storyboard"
+	"github.com/tsunyoku/danser/framework/graphics/batch"
+	"github.com/tsunyoku/danser/framework/math/animation"
+	color2 "github.com/tsunyoku/danser/framework/math/color"
+	"github.com/tsunyoku/danser/framework/math/vector"
+	"math"
+	"sort"
+	"strconv"
+)
+
+const strainColumns = 128
+
+var (
+	aimColor   = color2.Color{R: 0.4, G: 0.7, B: 1, A: 1}
+	speedColor = color2.Color{R: 1, G: 0.4, B: 0.7, A: 1}
+)
+
+// initWidgets prepares the strain graph from step star ratings of the cursor's mods
+func (overlay *ScoreOverlay) initWidgets() {
+	overlay.stepStars = overlay.ruleset.GetStepStars(overlay.cursor)
+
+	overlay.maxPPGlider = animation.NewTargetGlider(overlay.ruleset.GetMaxPP(overlay.cursor), 0)
+
+	hObjects := overlay.ruleset.GetBeatMap().HitObjects
+
+	overlay.strainStart = hObjects[0].GetStartTime()
+	overlay.strainEnd = math.Max(overlay.strainStart+1, hObjects[len(hObjects)-1].GetEndTime())
+
+	overlay.strains = make([]vector.Vector2d, strainColumns)
+
+	for i := 1; i < len(overlay.stepStars) && i < len(hObjects); i++ {
+		column := bmath.ClampI(int((hObjects[i].GetStartTime()-overlay.strainStart)/(overlay.strainEnd-overlay.strainStart)*strainColumns), 0, strainColumns-1)
+
+		overlay.strains[column].X = math.Max(overlay.strains[column].X, overlay.stepStars[i].AimStrain)
+		overlay.strains[column].Y = math.Max(overlay.strains[column].Y, overlay.stepStars[i].SpeedStrain)
+	}
+
+	maxStrain := 0.0
+	for _, s := range overlay.strains {
+		maxStrain = math.Max(maxStrain, s.X+s.Y)
+	}
+
+	if maxStrain > 0 {
+		for i := range overlay.strains {
+			overlay.strains[i] = overlay.strains[i].Scl(1 / maxStrain)
+		}
+	}
+}
+
+// getCurrentStars returns star rating of the map up to the last object that has started
+func (overlay *ScoreOverlay) getCurrentStars() (current, final int) {
+	hObjects := overlay.ruleset.GetBeatMap().HitObjects
+
+	index := sort.Search(len(hObjects), func(i int) bool {
+		return hObjects[i].GetStartTime() > overlay.audioTime
+	}) - 1
+
+	final = len(overlay.stepStars) - 1
+
+	return bmath.ClampI(index, 0, final), final
+}
+
+func (overlay *ScoreOverlay) drawWidgets(batch *batch.QuadBatch, alpha float64) {
+	overlay.drawStrainGraph(batch, alpha)
+	overlay.drawStarRating(batch, alpha)
+	overlay.drawBPM(batch, alpha)
+	overlay.drawRemaining(batch, alpha)
+}
+
+func (overlay *ScoreOverlay) drawStrainGraph(batch *batch.QuadBatch, alpha float64) {
+	graph := settings.Gameplay.StrainGraph
+
+	graphAlpha := graph.Opacity * alpha
+
+	if graphAlpha < 0.001 || !graph.Show {
+		return
+	}
+
+	width, height := graph.Width*graph.Scale, graph.Height*graph.Scale
+
+	box := newHUDBox(vector.NewVec2d(graph.XPosition, graph.YPosition), width, height, storyboard.Origin[graph.Align])
+
+	batch.Flush()
+
+	graphAlpha *= overlay.beginHUDComponent(batch, "StrainGraph", box)
+
+	progress := bmath.ClampF64((overlay.audioTime-overlay.strainStart)/(overlay.strainEnd-overlay.strainStart), 0, 1)
+	columnWidth := float32(width / strainColumns)
+
+	x, bottom := float32(box.min.X), float32(box.max.Y)
+
+	overlay.shapeRenderer.Begin()
+
+	overlay.shapeRenderer.SetColor(0, 0, 0, 0.4*graphAlpha)
+	overlay.shapeRenderer.DrawQuad(x, float32(box.min.Y), x, bottom, float32(box.max.X), bottom, float32(box.max.X), float32(box.min.Y))
+
+	for i, s := range overlay.strains {
+		cAlpha := graphAlpha
+		if (float64(i)+0.5)/strainColumns > progress {
+			cAlpha *= 0.4
+		}
+
+		x1 := x + float32(i)*columnWidth
+		x2 := x1 + columnWidth
+
+		aimTop := bottom - float32(s.X*height)
+		speedTop := aimTop - float32(s.Y*height)
+
+		overlay.shapeRenderer.SetColor(float64(aimColor.R), float64(aimColor.G), float64(aimColor.B), cAlpha)
+		overlay.shapeRenderer.DrawQuad(x1, aimTop, x1, bottom, x2, bottom, x2, aimTop)
+
+		overlay.shapeRenderer.SetColor(float64(speedColor.R), float64(speedColor.G), float64(speedColor.B), cAlpha)
+		overlay.shapeRenderer.DrawQuad(x1, speedTop, x1, aimTop, x2, aimTop, x2, speedTop)
+	}
+
+	playhead := x + float32(progress*width)
+
+	overlay.shapeRenderer.SetColor(1, 1, 1, graphAlpha)
+	overlay.shapeRenderer.DrawLine(playhead, float32(box.min.Y), playhead, bottom, float32(2*graph.Scale))
+
+	overlay.shapeRenderer.End()
+
+	overlay.endHUDComponent(batch)
+}
+
+// drawStarRating shows star rating of the map played so far next to the full one, with aim and speed bars below
+func (overlay *ScoreOverlay) drawStarRating(batch *batch.QuadBatch, alpha float64) {
+	widget := settings.Gameplay.StarRating
+
+	starAlpha := widget.Opacity * alpha
+
+	if starAlpha < 0.001 || !widget.Show || len(overlay.stepStars) == 0 {
+		return
+	}
+
+	scale := widget.Scale
+
+	index, finalIndex := overlay.getCurrentStars()
+	current, final := overlay.stepStars[index], overlay.stepStars[finalIndex]
+
+	box := newHUDBox(vector.NewVec2d(widget.XPosition, widget.YPosition), 200*scale, 52*scale, storyboard.Origin[widget.Align])
+
+	batch.Flush()
+
+	starAlpha *= overlay.beginHUDComponent(batch, "StarRating", box)
+
+	barLength := 150 * scale
+	maxStars := math.Max(0.01, math.Max(final.Aim, final.Speed))
+
+	bars := []struct {
+		value float64
+		color color2.Color
+	}{
+		{current.Aim, aimColor},
+		{current.Speed, speedColor},
+	}
+
+	overlay.shapeRenderer.Begin()
+
+	for i, bar := range bars {
+		x := float32(box.min.X)
+		y1 := float32(box.min.Y + (34+float64(i)*10)*scale)
+		y2 := y1 + float32(6*scale)
+
+		x2 := x + float32(barLength)
+		filled := x + float32(barLength*bmath.ClampF64(bar.value/maxStars, 0, 1))
+
+		overlay.shapeRenderer.SetColor(0, 0, 0, 0.4*starAlpha)
+		overlay.shapeRenderer.DrawQuad(x, y1, x, y2, x2, y2, x2, y1)
+
+		overlay.shapeRenderer.SetColor(float64(bar.color.R), float64(bar.color.G), float64(bar.color.B), starAlpha)
+		overlay.shapeRenderer.DrawQuad(x, y1, x, y2, filled, y2, filled, y1)
+	}
+
+	overlay.shapeRenderer.End()
+
+	overlay.drawWidgetText(batch, box.min, bmath.Origin.TopLeft, 28*scale, starAlpha, fmt.Sprintf("%.2f* / %.2f*", current.Total, final.Total))
+
+	for i, bar := range bars {
+		overlay.drawWidgetText(batch, box.min.AddS(barLength+6*scale, (37+float64(i)*10)*scale), bmath.Origin.CentreLeft, 10*scale, starAlpha, fmt.Sprintf("%.2f", bar.value))
+	}
+
+	overlay.endHUDComponent(batch)
+}
+
+func (overlay *ScoreOverlay) drawBPM(batch *batch.QuadBatch, alpha float64) {
+	widget := settings.Gameplay.BPMDisplay
+
+	bpmAlpha := widget.Opacity * alpha
+
+	if bpmAlpha < 0.001 || !widget.Show {
+		return
+	}
+
+	beatMap := overlay.ruleset.GetBeatMap()
+	point := beatMap.Timings.GetPoint(overlay.audioTime)
+
+	bpm := 60000 / point.BaseBpm * beatMap.Diff.Speed
+
+	text := fmt.Sprintf("%.0f BPM %.2fx", bpm, 1/point.GetRatio())
+
+	overlay.drawWidget(batch, "BPMDisplay", vector.NewVec2d(widget.XPosition, widget.YPosition), widget.Align, widget.Scale, bpmAlpha, text)
+}
+
+func (overlay *ScoreOverlay) drawRemaining(batch *batch.QuadBatch, alpha float64) {
+	widget := settings.Gameplay.RemainingCounter
+
+	remainingAlpha := widget.Opacity * alpha
+
+	if remainingAlpha < 0.001 || !widget.Show {
+		return
+	}
+
+	text := fmt.Sprintf("%d left", overlay.ruleset.GetRemaining(overlay.cursor))
+	if widget.Mode == "MaxPP" {
+		text = fmt.Sprintf("%."+strconv.Itoa(settings.Gameplay.PPCounter.Decimals)+"fpp max", overlay.maxPPGlider.GetValue())
+	}
+
+	overlay.drawWidget(batch, "RemainingCounter", vector.NewVec2d(widget.XPosition, widget.YPosition), widget.Align, widget.Scale, remainingAlpha, text)
+}
+
+// drawWidget draws a single line widget the same way PP counter is drawn
+func (overlay *ScoreOverlay) drawWidget(batch *batch.QuadBatch, name string, position vector.Vector2d, align string, scale, alpha float64, text string) {
+	size := 30 * scale
+	origin := storyboard.Origin[align]
+
+	alpha *= overlay.beginHUDComponent(batch, name, newHUDBox(position, overlay.ppFont.GetWidthMonospaced(size, text), size, origin))
+
+	overlay.drawWidgetText(batch, position, origin, size, alpha, text)
+
+	overlay.endHUDComponent(batch)
+}
+
+func (overlay *ScoreOverlay) drawWidgetText(batch *batch.QuadBatch, position, origin vector.Vector2d, size, alpha float64, text string) {
+	batch.ResetTransform()
+
+	batch.SetColor(0, 0, 0, alpha*0.8)
+	overlay.ppFont.DrawOriginV(batch, position.AddS(size/40, size/40), origin, size, true, text)
+	batch.SetColor(1, 1, 1, alpha)
+	overlay.ppFont.DrawOriginV(batch, position, origin, size, true, text)
+}
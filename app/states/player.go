@@ -3,6 +3,7 @@ package states
 import (
 	"fmt"
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/tsunyoku/danser/app/audio"
 	"github.com/tsunyoku/danser/app/beatmap"
 	"github.com/tsunyoku/danser/app/beatmap/difficulty"
 	"github.com/tsunyoku/danser/app/bmath"
@@ -11,10 +12,12 @@ import (
 	"github.com/tsunyoku/danser/app/discord"
 	"github.com/tsunyoku/danser/app/graphics"
 	"github.com/tsunyoku/danser/app/input"
+	"github.com/tsunyoku/danser/app/rulesets/osu"
 	"github.com/tsunyoku/danser/app/settings"
 	"github.com/tsunyoku/danser/app/states/components/common"
 	"github.com/tsunyoku/danser/app/states/components/containers"
 	"github.com/tsunyoku/danser/app/states/components/overlays"
+	"github.com/tsunyoku/danser/app/states/components/overlays/play"
 	"github.com/tsunyoku/danser/app/utils"
 	"github.com/tsunyoku/danser/framework/bass"
 	"github.com/tsunyoku/danser/framework/frame"
@@ -32,7 +35,6 @@ import (
 	"log"
 	"math"
 	"os"
-	"path/filepath"
 	"runtime"
 	"strconv"
 )
@@ -77,6 +79,8 @@ type Player struct {
 	Epi             *texture.TextureRegion
 	epiGlider       *animation.Glider
 	overlay         overlays.Overlay
+	keyHistory      *play.KeyHistory
+	keyCamera       *camera2.Camera
 	blur            *effects.BlurEffect
 
 	coin *common.DanserCoin
@@ -105,6 +109,16 @@ type Player struct {
 
 	ScaledWidth  float64
 	ScaledHeight float64
+
+	separateHUD  bool
+	cursorColors []color2.Color
+
+	speedPositions []vector.Vector2f
+	speedTime      float64
+
+	// passing is storyboard's pass/fail state, it's latched at break starts like in stable
+	passing   bool
+	nextPause int
 }
 
 func NewPlayer(beatMap *beatmap.BeatMap) *Player {
@@ -120,13 +134,14 @@ func NewPlayer(beatMap *beatmap.BeatMap) *Player {
 
 	player.font = font.GetFont("Exo 2 Bold")
 
-	discord.SetMap(beatMap.Artist, beatMap.Name, beatMap.Difficulty)
+	discord.SetMap(beatMap.GetArtist(), beatMap.GetName(), beatMap.Difficulty)
 
 	player.bMap = beatMap
-	player.mapFullName = fmt.Sprintf("%s - %s [%s]", beatMap.Artist, beatMap.Name, beatMap.Difficulty)
+	player.passing = true
+	player.mapFullName = fmt.Sprintf("%s - %s [%s]", beatMap.GetArtist(), beatMap.GetName(), beatMap.Difficulty)
 	log.Println("Playing:", player.mapFullName)
 
-	player.musicPlayer = bass.NewTrack(filepath.Join(settings.General.OsuSongsDir, beatMap.Dir, beatMap.Audio))
+	player.musicPlayer = beatMap.LoadTrack()
 
 	var err error
 	player.Epi, err = utils.LoadTextureToAtlas(graphics.Atlas, "assets/textures/warning.png")
@@ -176,6 +191,10 @@ func NewPlayer(beatMap *beatmap.BeatMap) *Player {
 	player.background = common.NewBackground()
 	player.background.SetBeatmap(beatMap, settings.Playfield.Background.LoadStoryboards)
 
+	if storyboard := player.background.GetStoryboard(); storyboard != nil {
+		audio.AddHitSoundListener(storyboard.TriggerHitSound)
+	}
+
 	player.mainCamera = camera2.NewCamera()
 	player.mainCamera.SetOsuViewport(int(settings.Graphics.GetWidth()), int(settings.Graphics.GetHeight()), settings.Playfield.Scale, settings.Playfield.OsuShift)
 	player.mainCamera.Update()
@@ -220,10 +239,29 @@ func NewPlayer(beatMap *beatmap.BeatMap) *Player {
 		} else {
 			player.overlay = overlays.NewKnockoutOverlay(controller.(*dance.ReplayController))
 		}
+	} else if settings.Dance.Battle && settings.TAG > 1 {
+		controller := dance.NewBattleController()
+		player.controller = controller
+
+		player.controller.SetBeatMap(player.bMap)
+		player.controller.InitCursors()
+
+		player.overlay = overlays.NewKnockoutOverlay(controller.(*dance.ReplayController))
 	} else {
 		player.controller = dance.NewGenericController()
 		player.controller.SetBeatMap(player.bMap)
 		player.controller.InitCursors()
+
+		if settings.Gameplay.KeyOverlay.Style == "Bars" {
+			// Cursordance doesn't have a score overlay, so key history is drawn on its own with the same 768px high HUD space
+			keyWidth := settings.Graphics.GetAspectRatio() * 768
+
+			player.keyCamera = camera2.NewCamera()
+			player.keyCamera.SetViewportF(0, 768, int(keyWidth), 0)
+			player.keyCamera.Update()
+
+			player.keyHistory = play.NewKeyHistory(player.controller.GetCursors()[0], keyWidth, 768)
+		}
 	}
 
 	player.lastTime = -1
@@ -525,6 +563,42 @@ func (player *Player) Update(delta float64) bool {
 	return false
 }
 
+// updatePassing evaluates pass/fail state once per break, so HP hovering around the threshold doesn't make Pass/Fail layers flicker
+func (player *Player) updatePassing() bool {
+	pauses := player.bMap.Pauses
+
+	for player.nextPause < len(pauses) && player.progressMsF >= pauses[player.nextPause].StartTime {
+		player.passing = player.isPassing()
+		player.nextPause++
+	}
+
+	return player.passing
+}
+
+// isPassing checks HP of the current cursor, in knockout it's the first one that's still alive
+func (player *Player) isPassing() bool {
+	var ruleset *osu.OsuRuleSet
+
+	switch controller := player.controller.(type) {
+	case *dance.PlayerController:
+		ruleset = controller.GetRuleset()
+	case *dance.ReplayController:
+		ruleset = controller.GetRuleset()
+	default:
+		return true
+	}
+
+	for _, cursor := range player.controller.GetCursors() {
+		if player.overlay != nil && player.overlay.IsBroken(cursor) {
+			continue
+		}
+
+		return ruleset.GetHP(cursor) >= 0.5
+	}
+
+	return false
+}
+
 func (player *Player) GetTime() float64 {
 	return player.progressMsF
 }
@@ -587,6 +661,10 @@ func (player *Player) updateMain(delta float64) {
 		player.overlay.Update(player.progressMsF)
 	}
 
+	if player.keyHistory != nil {
+		player.keyHistory.Update(player.progressMsF)
+	}
+
 	player.updateMusic(delta)
 
 	player.coin.Update(player.progressMsF)
@@ -600,6 +678,10 @@ func (player *Player) updateMain(delta float64) {
 
 	offset = offset.Scl(1 / float64(len(player.controller.GetCursors())))
 
+	if storyboard := player.background.GetStoryboard(); storyboard != nil {
+		storyboard.SetPassing(player.updatePassing())
+	}
+
 	player.background.Update(player.progressMsF, offset.X*player.cursorGlider.GetValue(), offset.Y*player.cursorGlider.GetValue())
 
 	player.epiGlider.Update(player.progressMsF)
@@ -617,7 +699,7 @@ func (player *Player) updateMain(delta float64) {
 }
 
 func (player *Player) updateMusic(delta float64) {
-	player.musicPlayer.Update()
+	player.musicPlayer.Update(player.progressMsF)
 
 	target := bmath.ClampF64(player.musicPlayer.GetBoost()*(settings.Audio.BeatScale-1.0)+1.0, 1.0, settings.Audio.BeatScale)
 
@@ -655,7 +737,7 @@ func (player *Player) Draw(float64) {
 	player.profiler.PutSample(timMs)
 	player.lastTime = tim
 
-	cameras := player.mainCamera.GenRotated(settings.DIVIDES, -2*math.Pi/float64(settings.DIVIDES))
+	cameras := player.getCameras()
 
 	bgAlpha := player.dimGlider.GetValue()
 	if settings.Playfield.Background.FlashToTheBeat {
@@ -669,6 +751,8 @@ func (player *Player) Draw(float64) {
 	}
 
 	cursorColors := settings.Cursor.GetColors(settings.DIVIDES, len(player.controller.GetCursors()), player.Scl, player.cursorGlider.GetValue())
+	cursorColors = settings.Playfield.Symmetry.AdjustColors(cursorColors, len(player.controller.GetCursors()))
+	player.cursorColors = cursorColors
 
 	if player.overlay != nil {
 		player.batch.Begin()
@@ -725,7 +809,8 @@ func (player *Player) Draw(float64) {
 
 	player.background.DrawOverlay(player.progressMsF, player.batch, bgAlpha, player.bgCamera.GetProjectionView())
 
-	if player.overlay != nil && player.overlay.ShouldDrawHUDBeforeCursor() {
+	// HUD under the cursor can't be composited separately without covering it, so it's always drawn here
+	if player.isHUDBeforeCursor() {
 		player.drawHUD(cursorColors)
 	}
 
@@ -741,11 +826,19 @@ func (player *Player) Draw(float64) {
 		for j := 0; j < settings.DIVIDES; j++ {
 			player.batch.SetCamera(cameras[j])
 
+			delay := settings.Playfield.Symmetry.GetCopy(j).CursorDelay
+
 			for i, g := range player.controller.GetCursors() {
 				if player.overlay != nil && player.overlay.IsBroken(g) {
 					continue
 				}
 
+				// Delayed copies move the whole cursor with its trail to the position it had delay ms ago
+				if delay > 0 {
+					offset := g.GetDelayedPosition(delay).Sub(g.Position)
+					player.batch.SetCamera(cameras[j].Mul4(mgl32.Translate3D(offset.X, offset.Y, 0)))
+				}
+
 				baseIndex := j*len(player.controller.GetCursors()) + i
 
 				ind := baseIndex - 1
@@ -757,6 +850,10 @@ func (player *Player) Draw(float64) {
 				col2 := cursorColors[ind]
 
 				g.DrawM(scale2, player.batch, col1, col2)
+
+				if delay > 0 {
+					player.batch.SetCamera(cameras[j])
+				}
 			}
 		}
 
@@ -765,7 +862,7 @@ func (player *Player) Draw(float64) {
 
 	player.batch.SetAdditive(false)
 
-	if player.overlay != nil && !player.overlay.ShouldDrawHUDBeforeCursor() {
+	if !player.isHUDBeforeCursor() && (player.overlay != nil || player.keyHistory != nil) && !player.separateHUD {
 		player.drawHUD(cursorColors)
 	}
 
@@ -776,6 +873,41 @@ func (player *Player) Draw(float64) {
 	player.drawDebug()
 }
 
+// getCameras returns camera of every playfield copy, symmetry settings add per-copy transformations on top of rotation
+func (player *Player) getCameras() []mgl32.Mat4 {
+	rotOffset := -2 * math.Pi / float64(settings.DIVIDES)
+
+	if !settings.Playfield.Symmetry.Enabled {
+		return player.mainCamera.GenRotated(settings.DIVIDES, rotOffset)
+	}
+
+	centre := mgl32.Translate3D(512/2, 384/2, 0)
+
+	local := make([]mgl32.Mat4, settings.DIVIDES)
+
+	for i := range local {
+		sCopy := settings.Playfield.Symmetry.GetCopy(i)
+
+		scaleX, scaleY := float32(sCopy.Scale), float32(sCopy.Scale)
+
+		if sCopy.MirrorX != (settings.Playfield.Symmetry.Kaleidoscope && i%2 == 1) {
+			scaleX = -scaleX
+		}
+
+		if sCopy.MirrorY {
+			scaleY = -scaleY
+		}
+
+		local[i] = centre.
+			Mul4(mgl32.Translate3D(float32(sCopy.OffsetX), float32(sCopy.OffsetY), 0)).
+			Mul4(mgl32.HomogRotate3DZ(float32(sCopy.Rotation * math.Pi / 180))).
+			Mul4(mgl32.Scale3D(scaleX, scaleY, 1)).
+			Mul4(centre.Inv())
+	}
+
+	return player.mainCamera.GenTransformed(settings.DIVIDES, rotOffset, local)
+}
+
 func (player *Player) drawEpilepsyWarning() {
 	if player.epiGlider.GetValue() < 0.01 {
 		return
@@ -796,13 +928,63 @@ func (player *Player) drawEpilepsyWarning() {
 	player.batch.SetColor(1, 1, 1, 1)
 }
 
+// SetSeparateHUD excludes HUD drawn above the cursor from Draw, it has to be drawn with DrawHUD instead
+func (player *Player) SetSeparateHUD(value bool) {
+	player.separateHUD = value
+}
+
+func (player *Player) isHUDBeforeCursor() bool {
+	return player.overlay != nil && player.overlay.ShouldDrawHUDBeforeCursor()
+}
+
+func (player *Player) DrawHUD() {
+	if (player.overlay == nil && player.keyHistory == nil) || player.cursorColors == nil || player.isHUDBeforeCursor() {
+		return
+	}
+
+	player.drawHUD(player.cursorColors)
+}
+
+// GetCursorSpeed returns the speed of the fastest cursor (in osu!pixels per millisecond) since the previous call
+func (player *Player) GetCursorSpeed() float64 {
+	cursors := player.controller.GetCursors()
+
+	speed := 0.0
+
+	if elapsed := player.progressMsF - player.speedTime; len(player.speedPositions) == len(cursors) && elapsed > 0 {
+		for i, c := range cursors {
+			speed = math.Max(speed, float64(c.Position.Dst(player.speedPositions[i]))/elapsed)
+		}
+	}
+
+	player.speedPositions = player.speedPositions[:0]
+
+	for _, c := range cursors {
+		player.speedPositions = append(player.speedPositions, c.Position)
+	}
+
+	player.speedTime = player.progressMsF
+
+	return speed
+}
+
 func (player *Player) drawHUD(cursorColors []color2.Color) {
 	player.batch.Begin()
 	player.batch.SetScale(1, 1)
 
 	player.batch.SetCamera(player.uiCamera.GetProjectionView())
 
-	player.overlay.DrawHUD(player.batch, cursorColors, player.hudGlider.GetValue())
+	if player.overlay != nil {
+		player.overlay.DrawHUD(player.batch, cursorColors, player.hudGlider.GetValue())
+	}
+
+	if player.keyHistory != nil {
+		player.batch.SetCamera(player.keyCamera.GetProjectionView())
+		player.batch.ResetTransform()
+		player.batch.SetColor(1, 1, 1, 1)
+
+		player.keyHistory.Draw(player.batch, player.hudGlider.GetValue())
+	}
 
 	player.batch.End()
 }
@@ -936,4 +1118,7 @@ func (player *Player) Show() {}
 
 func (player *Player) Hide() {}
 
-func (player *Player) Dispose() {}
+func (player *Player) Dispose() {
+	player.controller.Dispose()
+	player.musicPlayer.Dispose()
+}
@@ -21,10 +21,12 @@ func cutWhites(text string) (string, int) {
 	return text, 0
 }
 
-func parseCommands(commands []string) []*animation.Transformation {
+func parseCommands(commands []string) ([]*animation.Transformation, []*TriggerProcessor) {
 	transforms := make([]*animation.Transformation, 0)
+	triggers := make([]*TriggerProcessor, 0)
 
 	var currentLoop *LoopProcessor = nil
+	var currentTrigger *TriggerProcessor = nil
 
 	loopDepth := -1
 
@@ -34,10 +36,6 @@ func parseCommands(commands []string) []*animation.Transformation {
 		var removed int
 		command[0], removed = cutWhites(command[0])
 
-		if command[0] == "T" {
-			continue
-		}
-
 		if removed == 1 {
 			if currentLoop != nil {
 				transforms = append(transforms, currentLoop.Unwind()...)
@@ -46,7 +44,14 @@ func parseCommands(commands []string) []*animation.Transformation {
 				loopDepth = -1
 			}
 
-			if command[0] != "L" {
+			if currentTrigger != nil {
+				triggers = append(triggers, currentTrigger)
+
+				currentTrigger = nil
+				loopDepth = -1
+			}
+
+			if command[0] != "L" && command[0] != "T" {
 				transforms = append(transforms, parseCommand(command)...)
 			}
 		}
@@ -54,11 +59,20 @@ func parseCommands(commands []string) []*animation.Transformation {
 		if command[0] == "L" {
 			currentLoop = NewLoopProcessor(command)
 			loopDepth = removed + 1
+		} else if command[0] == "T" {
+			currentTrigger = NewTriggerProcessor(command)
+			loopDepth = removed + 1
 		} else if removed == loopDepth && currentLoop != nil {
 			currentLoop.Add(command)
+		} else if removed == loopDepth && currentTrigger != nil {
+			currentTrigger.Add(command)
 		}
 	}
 
+	if currentTrigger != nil {
+		triggers = append(triggers, currentTrigger)
+	}
+
 	if currentLoop != nil {
 		transforms = append(transforms, currentLoop.Unwind()...)
 
@@ -66,7 +80,7 @@ func parseCommands(commands []string) []*animation.Transformation {
 		loopDepth = -1
 	}
 
-	return transforms
+	return transforms, triggers
 }
 
 func parseCommand(data []string) []*animation.Transformation {
@@ -2,11 +2,13 @@ package storyboard
 
 import (
 	"fmt"
+	"github.com/tsunyoku/danser/app/audio"
 	"github.com/tsunyoku/danser/app/beatmap"
 	"github.com/tsunyoku/danser/app/bmath"
 	"github.com/tsunyoku/danser/app/settings"
 	"github.com/tsunyoku/danser/app/skin"
 	"github.com/tsunyoku/danser/app/utils"
+	"github.com/tsunyoku/danser/framework/bass"
 	"github.com/tsunyoku/danser/framework/frame"
 	"github.com/tsunyoku/danser/framework/graphics/batch"
 	"github.com/tsunyoku/danser/framework/graphics/sprite"
@@ -17,19 +19,28 @@ import (
 	"github.com/tsunyoku/danser/framework/util"
 	"log"
 	"math"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 )
 
+const maxSampleDelay = 100.0
+
 type Storyboard struct {
 	textures    map[string]*texture.TextureRegion
 	atlas       *texture.TextureAtlas
 	background  *sprite.SpriteManager
 	pass        *sprite.SpriteManager
+	fail        *sprite.SpriteManager
 	foreground  *sprite.SpriteManager
 	overlay     *sprite.SpriteManager
+	samples     *sprite.SpriteManager
+	sampleCache map[string]*bass.Sample
+	passing     bool
+	triggers    []*TriggerProcessor
+	events      []triggerEvent
+	eventMutex  *sync.Mutex
 	zIndex      int64
 	bgFileUsed  bool
 	widescreen  bool
@@ -66,11 +77,12 @@ func NewStoryboard(beatMap *beatmap.BeatMap) *Storyboard {
 		return replacer.Replace(el)
 	}
 
-	files := []string{filepath.Join(path, beatMap.File), filepath.Join(path, fmt.Sprintf("%s - %s (%s).osb", fix(beatMap.Artist), fix(beatMap.Name), fix(beatMap.Creator)))}
+	files := []string{beatMap.File, fmt.Sprintf("%s - %s (%s).osb", fix(beatMap.Artist), fix(beatMap.Name), fix(beatMap.Creator))}
 
-	storyboard := &Storyboard{zIndex: -1, background: sprite.NewSpriteManager(), pass: sprite.NewSpriteManager(), foreground: sprite.NewSpriteManager(), overlay: sprite.NewSpriteManager(), atlas: nil}
+	storyboard := &Storyboard{zIndex: -1, background: sprite.NewSpriteManager(), pass: sprite.NewSpriteManager(), fail: sprite.NewSpriteManager(), foreground: sprite.NewSpriteManager(), overlay: sprite.NewSpriteManager(), samples: sprite.NewSpriteManager(), atlas: nil, passing: true, eventMutex: &sync.Mutex{}}
 	storyboard.textures = make(map[string]*texture.TextureRegion)
-	storyboard.pathCache = utils.NewFileMap(path)
+	storyboard.sampleCache = make(map[string]*bass.Sample)
+	storyboard.pathCache = beatMap.GetFileMap()
 
 	var currentSection string
 	var currentSprite string
@@ -81,9 +93,9 @@ func NewStoryboard(beatMap *beatmap.BeatMap) *Storyboard {
 	hasVideo := false
 
 	for _, fS := range files {
-		file, err := os.Open(fS)
+		file, err := storyboard.pathCache.Open(fS)
 
-		log.Println("Trying to load storyboard from: ", fS)
+		log.Println("Trying to load storyboard from: ", filepath.Join(path, fS))
 
 		if err != nil {
 			log.Println(err)
@@ -126,6 +138,12 @@ func NewStoryboard(beatMap *beatmap.BeatMap) *Storyboard {
 				if settings.Playfield.Background.LoadVideos && (strings.HasPrefix(line, "Video") || strings.HasPrefix(line, "1")) {
 					spl := strings.Split(line, ",")
 
+					// Video decoder needs a real file
+					if beatMap.IsArchived() {
+						log.Println("Videos can't be loaded from .osz archives, skipping:", spl[2])
+						continue
+					}
+
 					log.Println(filepath.Join(path, fix(spl[2])))
 
 					video := video2.NewVideo(filepath.Join(path, fix(spl[2])), -1, vector.NewVec2d(320, 240), bmath.Origin.Centre)
@@ -155,6 +173,10 @@ func NewStoryboard(beatMap *beatmap.BeatMap) *Storyboard {
 						commands = make([]string, 0)
 					} else if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "_") {
 						commands = append(commands, line)
+					} else if strings.HasPrefix(line, "Sample") || strings.HasPrefix(line, "5") {
+						if storyboard.loadSample(line) {
+							counter++
+						}
 					}
 				}
 			}
@@ -243,16 +265,34 @@ func (storyboard *Storyboard) loadSprite(path, currentSprite string, commands []
 	if len(textures) != 0 {
 		sbSprite := sprite.NewAnimation(textures, frameDelay, loopForever, float64(storyboard.zIndex), pos, origin)
 
-		transforms := parseCommands(commands)
+		transforms, triggers := parseCommands(commands)
 
 		sbSprite.ShowForever(false)
 		sbSprite.AddTransforms(transforms)
 		sbSprite.AdjustTimesToTransformations()
 		sbSprite.ResetValuesToTransforms()
 
+		if len(triggers) > 0 {
+			startTime, endTime := sbSprite.GetStartTime(), sbSprite.GetEndTime()
+
+			for _, t := range triggers {
+				t.sprite = sbSprite
+
+				startTime = math.Min(startTime, t.start)
+				endTime = math.Max(endTime, t.end+t.duration)
+			}
+
+			sbSprite.SetStartTime(startTime)
+			sbSprite.SetEndTime(endTime)
+
+			storyboard.triggers = append(storyboard.triggers, triggers...)
+		}
+
 		switch spl[1] {
 		case "0", "Background":
 			storyboard.background.Add(sbSprite)
+		case "1", "Fail":
+			storyboard.fail.Add(sbSprite)
 		case "2", "Pass":
 			storyboard.pass.Add(sbSprite)
 		case "3", "Foreground":
@@ -265,6 +305,71 @@ func (storyboard *Storyboard) loadSprite(path, currentSprite string, commands []
 	}
 }
 
+// loadSample parses Sample,time,layer,"file",volume line, returns false if sample couldn't be loaded
+func (storyboard *Storyboard) loadSample(line string) bool {
+	spl := strings.Split(line, ",")
+	if len(spl) < 4 {
+		return false
+	}
+
+	time, err := strconv.ParseFloat(strings.TrimSpace(spl[1]), 64)
+	if err != nil {
+		return false
+	}
+
+	volume := 100.0
+	if len(spl) > 4 {
+		if v, err := strconv.ParseFloat(strings.TrimSpace(spl[4]), 64); err == nil {
+			volume = v
+		}
+	}
+
+	file := strings.Replace(spl[3], `"`, "", -1)
+
+	sample, ok := storyboard.sampleCache[file]
+	if !ok {
+		if data, err := storyboard.pathCache.ReadFile(file); err == nil {
+			sample = bass.NewSampleData(data)
+		}
+
+		if sample == nil {
+			log.Println("Sample:", file, "does not exist!")
+		} else {
+			sample.SetBus(bass.BusStoryboard)
+		}
+
+		storyboard.sampleCache[file] = sample
+	}
+
+	if sample == nil {
+		return false
+	}
+
+	aSprite := audio.NewAudioSpriteV(sample, time, bmath.ClampF64(volume, 0, 100)/100)
+
+	layer := strings.TrimSpace(spl[2])
+
+	aSprite.SetCondition(func(t float64) bool {
+		// Don't play samples we've skipped over
+		if t-time > maxSampleDelay {
+			return false
+		}
+
+		switch layer {
+		case "1", "Fail":
+			return !storyboard.passing
+		case "2", "Pass":
+			return storyboard.passing
+		}
+
+		return true
+	})
+
+	storyboard.samples.Add(aSprite)
+
+	return true
+}
+
 func (storyboard *Storyboard) getTexture(image string) *texture.TextureRegion {
 	var texture1 *texture.TextureRegion
 
@@ -272,13 +377,13 @@ func (storyboard *Storyboard) getTexture(image string) *texture.TextureRegion {
 		if texture1 = skin.GetTexture(strings.TrimSuffix(image, filepath.Ext(image))); texture1 != nil {
 			storyboard.textures[image] = texture1
 		} else {
-			path, err := storyboard.pathCache.GetFile(image)
-			if err != nil {
+			data, err := storyboard.pathCache.ReadFile(image)
+			if err != nil || len(data) == 0 {
 				log.Println("File:", image, "does not exist!")
 				return texture1
 			}
 
-			img, err := texture.NewPixmapFileString(path)
+			img, err := texture.NewPixmapFromBytes(data)
 
 			if err == nil {
 
@@ -352,16 +457,90 @@ func (storyboard *Storyboard) SetFPS(i int) {
 }
 
 func (storyboard *Storyboard) Update(time float64) {
+	storyboard.processEvents(time)
+
 	storyboard.background.Update(time)
+	storyboard.fail.Update(time)
 	storyboard.pass.Update(time)
 	storyboard.foreground.Update(time)
 	storyboard.overlay.Update(time)
+	storyboard.samples.Update(time)
+}
+
+// SetPassing sets whether Pass or Fail layer is active, it's passing by default. Changes fire Passing/Failing triggers
+func (storyboard *Storyboard) SetPassing(passing bool) {
+	if storyboard.passing == passing {
+		return
+	}
+
+	storyboard.passing = passing
+
+	if passing {
+		storyboard.queueEvent(triggerEvent{name: "Passing"})
+	} else {
+		storyboard.queueEvent(triggerEvent{name: "Failing"})
+	}
+}
+
+// TriggerHitSound fires HitSound triggers, arguments match audio.PlaySample
+func (storyboard *Storyboard) TriggerHitSound(sampleSet, additionSet, hitsound, index int) {
+	storyboard.queueEvent(triggerEvent{
+		name:        "HitSound",
+		sampleSet:   sampleSet,
+		additionSet: additionSet,
+		hitsound:    hitsound,
+		index:       index,
+	})
+}
+
+func (storyboard *Storyboard) queueEvent(event triggerEvent) {
+	if len(storyboard.triggers) == 0 {
+		return
+	}
+
+	storyboard.eventMutex.Lock()
+	storyboard.events = append(storyboard.events, event)
+	storyboard.eventMutex.Unlock()
+}
+
+// processEvents activates triggers on storyboard's thread so sprites aren't modified concurrently
+func (storyboard *Storyboard) processEvents(time float64) {
+	storyboard.eventMutex.Lock()
+	events := storyboard.events
+	storyboard.events = nil
+	storyboard.eventMutex.Unlock()
+
+	for _, event := range events {
+		for _, trigger := range storyboard.triggers {
+			if time < trigger.start || time > trigger.end || !trigger.matches(event) {
+				continue
+			}
+
+			for _, other := range storyboard.triggers {
+				if other != trigger && other.sprite == trigger.sprite && other.group == trigger.group {
+					other.cancel()
+				}
+			}
+
+			trigger.activate(time)
+		}
+	}
+}
+
+func (storyboard *Storyboard) IsPassing() bool {
+	return storyboard.passing
 }
 
 func (storyboard *Storyboard) Draw(time float64, batch *batch.QuadBatch) {
 	batch.SetTranslation(vector.NewVec2d(-64, -48))
 	storyboard.background.Draw(time, batch)
-	storyboard.pass.Draw(time, batch)
+
+	if storyboard.passing {
+		storyboard.pass.Draw(time, batch)
+	} else {
+		storyboard.fail.Draw(time, batch)
+	}
+
 	storyboard.foreground.Draw(time, batch)
 	batch.SetTranslation(vector.NewVec2d(0, 0))
 }
@@ -373,15 +552,15 @@ func (storyboard *Storyboard) DrawOverlay(time float64, batch *batch.QuadBatch)
 }
 
 func (storyboard *Storyboard) GetRenderedSprites() int {
-	return storyboard.background.GetNumRendered() + storyboard.pass.GetNumRendered() + storyboard.foreground.GetNumRendered() + storyboard.overlay.GetNumRendered()
+	return storyboard.background.GetNumRendered() + storyboard.fail.GetNumRendered() + storyboard.pass.GetNumRendered() + storyboard.foreground.GetNumRendered() + storyboard.overlay.GetNumRendered()
 }
 
 func (storyboard *Storyboard) GetProcessedSprites() int {
-	return storyboard.background.GetNumProcessed() + storyboard.pass.GetNumProcessed() + storyboard.foreground.GetNumProcessed() + storyboard.overlay.GetNumProcessed()
+	return storyboard.background.GetNumProcessed() + storyboard.fail.GetNumProcessed() + storyboard.pass.GetNumProcessed() + storyboard.foreground.GetNumProcessed() + storyboard.overlay.GetNumProcessed()
 }
 
 func (storyboard *Storyboard) GetQueueSprites() int {
-	return storyboard.background.GetNumInQueue() + storyboard.pass.GetNumInQueue() + storyboard.foreground.GetNumInQueue() + storyboard.overlay.GetNumInQueue()
+	return storyboard.background.GetNumInQueue() + storyboard.fail.GetNumInQueue() + storyboard.pass.GetNumInQueue() + storyboard.foreground.GetNumInQueue() + storyboard.overlay.GetNumInQueue()
 }
 
 func (storyboard *Storyboard) GetTotalSprites() int {
@@ -389,7 +568,7 @@ func (storyboard *Storyboard) GetTotalSprites() int {
 }
 
 func (storyboard *Storyboard) GetLoad() float64 {
-	return storyboard.background.GetLoad() + storyboard.pass.GetLoad() + storyboard.foreground.GetLoad() + storyboard.overlay.GetLoad()
+	return storyboard.background.GetLoad() + storyboard.fail.GetLoad() + storyboard.pass.GetLoad() + storyboard.foreground.GetLoad() + storyboard.overlay.GetLoad()
 }
 
 func (storyboard *Storyboard) BGFileUsed() bool {
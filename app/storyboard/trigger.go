@@ -0,0 +1,153 @@
+package storyboard
+
+import (
+	"github.com/tsunyoku/danser/framework/graphics/sprite"
+	"github.com/tsunyoku/danser/framework/math/animation"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var sampleSets = []string{"Normal", "Soft", "Drum"}
+
+var additions = map[string]int{
+	"Whistle": 2,
+	"Finish":  4,
+	"Clap":    8,
+}
+
+type triggerEvent struct {
+	name string
+
+	sampleSet   int
+	additionSet int
+	hitsound    int
+	index       int
+}
+
+type TriggerProcessor struct {
+	name       string
+	start, end float64
+	group      int64
+
+	hitSound    bool
+	sampleSet   int
+	additionSet int
+	addition    int
+	index       int
+
+	duration   float64
+	transforms []*animation.Transformation
+
+	sprite *sprite.Sprite
+	active []*animation.Transformation
+}
+
+func NewTriggerProcessor(data []string) *TriggerProcessor {
+	trigger := &TriggerProcessor{name: data[1], index: -1}
+
+	start, err := strconv.ParseInt(data[2], 10, 64)
+	if err != nil {
+		log.Println("Failed to parse: ", data)
+		panic(err)
+	}
+
+	end, err := strconv.ParseInt(data[3], 10, 64)
+	if err != nil {
+		log.Println("Failed to parse: ", data)
+		panic(err)
+	}
+
+	trigger.start, trigger.end = float64(start), float64(end)
+
+	if len(data) > 4 {
+		trigger.group, _ = strconv.ParseInt(data[4], 10, 64)
+	}
+
+	if strings.HasPrefix(trigger.name, "HitSound") {
+		trigger.hitSound = true
+		trigger.parseHitSound(strings.TrimPrefix(trigger.name, "HitSound"))
+	}
+
+	return trigger
+}
+
+// parseHitSound parses the remainder of HitSound[SampleSet][AdditionsSampleSet][Addition][CustomSampleSet]
+func (trigger *TriggerProcessor) parseHitSound(name string) {
+	parseSet := func() int {
+		for i, s := range sampleSets {
+			if strings.HasPrefix(name, s) {
+				name = strings.TrimPrefix(name, s)
+				return i + 1
+			}
+		}
+
+		if strings.HasPrefix(name, "All") {
+			name = strings.TrimPrefix(name, "All")
+		}
+
+		return 0
+	}
+
+	trigger.sampleSet = parseSet()
+	trigger.additionSet = parseSet()
+
+	for k, v := range additions {
+		if strings.HasPrefix(name, k) {
+			trigger.addition = v
+			name = strings.TrimPrefix(name, k)
+
+			break
+		}
+	}
+
+	if index, err := strconv.Atoi(name); err == nil {
+		trigger.index = index
+	}
+}
+
+func (trigger *TriggerProcessor) Add(command []string) {
+	transforms := parseCommand(command)
+
+	for _, t := range transforms {
+		trigger.duration = math.Max(trigger.duration, t.GetEndTime())
+	}
+
+	trigger.transforms = append(trigger.transforms, transforms...)
+}
+
+func (trigger *TriggerProcessor) matches(event triggerEvent) bool {
+	if !trigger.hitSound {
+		return trigger.name == event.name
+	}
+
+	if event.name != "HitSound" {
+		return false
+	}
+
+	return (trigger.sampleSet == 0 || trigger.sampleSet == event.sampleSet) &&
+		(trigger.additionSet == 0 || trigger.additionSet == event.additionSet) &&
+		(trigger.addition == 0 || event.hitsound&trigger.addition > 0) &&
+		(trigger.index == -1 || trigger.index == event.index)
+}
+
+func (trigger *TriggerProcessor) cancel() {
+	if trigger.active != nil {
+		trigger.sprite.RemoveTransformations(trigger.active)
+		trigger.active = nil
+	}
+}
+
+// activate plays trigger's commands relative to time, cancelling the previous activation
+func (trigger *TriggerProcessor) activate(time float64) {
+	trigger.cancel()
+
+	trigger.active = make([]*animation.Transformation, 0, len(trigger.transforms))
+
+	for _, t := range trigger.transforms {
+		trigger.active = append(trigger.active, t.Clone(time+t.GetStartTime(), time+t.GetEndTime()))
+	}
+
+	trigger.sprite.AddTransforms(trigger.active)
+}
@@ -1,18 +1,30 @@
 package utils
 
 import (
+	"archive/zip"
 	"github.com/karrick/godirwalk"
+	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
+// FileMap gives case-insensitive access to files in a directory or a zip archive (.osk/.osz) read in place
 type FileMap struct {
 	path      string
 	pathCache map[string]string
+	archive   bool
+
+	reader  *zip.ReadCloser
+	entries map[string]*zip.File
 }
 
 func NewFileMap(path string) *FileMap {
+	if stat, err := os.Stat(path); err == nil && !stat.IsDir() {
+		return newArchiveMap(path)
+	}
+
 	fPath := strings.ReplaceAll(path, "\\", "/")
 	if !strings.HasSuffix(fPath, "/") {
 		fPath += "/"
@@ -37,7 +49,46 @@ func NewFileMap(path string) *FileMap {
 	return fileMap
 }
 
+// newArchiveMap indexes zip entries, archive is kept open until Close is called
+func newArchiveMap(path string) *FileMap {
+	fileMap := &FileMap{
+		path:      path,
+		pathCache: make(map[string]string),
+		archive:   true,
+		entries:   make(map[string]*zip.File),
+	}
+
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return fileMap
+	}
+
+	fileMap.reader = reader
+
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		fixedPath := strings.TrimPrefix(strings.ReplaceAll(f.Name, "\\", "/"), "/")
+
+		fileMap.pathCache[strings.ToLower(fixedPath)] = f.Name
+		fileMap.entries[strings.ToLower(fixedPath)] = f
+	}
+
+	return fileMap
+}
+
+// IsArchive reports whether files are read from a zip archive, GetFile can't be used then
+func (f *FileMap) IsArchive() bool {
+	return f.archive
+}
+
 func (f *FileMap) GetFile(path string) (string, error) {
+	if f.archive {
+		return "", os.ErrNotExist
+	}
+
 	sPath := strings.ToLower(f.path)
 	fPath := strings.TrimPrefix(strings.ReplaceAll(strings.ToLower(path), "\\", "/"), sPath)
 
@@ -46,4 +97,69 @@ func (f *FileMap) GetFile(path string) (string, error) {
 	}
 
 	return "", os.ErrNotExist
-}
\ No newline at end of file
+}
+
+// Exists checks whether path is indexed, works for both directories and archives
+func (f *FileMap) Exists(path string) bool {
+	_, ok := f.pathCache[f.key(path)]
+	return ok
+}
+
+func (f *FileMap) key(path string) string {
+	return strings.TrimPrefix(strings.ReplaceAll(strings.ToLower(path), "\\", "/"), "/")
+}
+
+// Open opens the file for reading regardless of whether it's on disk or in the archive
+func (f *FileMap) Open(path string) (io.ReadCloser, error) {
+	if !f.archive {
+		resolved, err := f.GetFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return os.Open(resolved)
+	}
+
+	file, ok := f.entries[f.key(path)]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+
+	return file.Open()
+}
+
+// Close releases the archive, files can't be opened afterwards
+func (f *FileMap) Close() error {
+	if f.reader == nil {
+		return nil
+	}
+
+	err := f.reader.Close()
+
+	f.reader = nil
+	f.entries = make(map[string]*zip.File)
+
+	return err
+}
+
+func (f *FileMap) ReadFile(path string) ([]byte, error) {
+	file, err := f.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return ioutil.ReadAll(file)
+}
+
+// GetFiles returns relative paths of all indexed files
+func (f *FileMap) GetFiles() []string {
+	files := make([]string, 0, len(f.pathCache))
+
+	for _, path := range f.pathCache {
+		files = append(files, path)
+	}
+
+	return files
+}
@@ -0,0 +1,198 @@
+package bass
+
+/*
+#include "bass_util.h"
+#include "bass.h"
+*/
+import "C"
+
+import (
+	"log"
+	"math"
+	"unsafe"
+)
+
+const (
+	analysisFPS    = 50
+	analysisWindow = 1024
+	analysisBins   = 256
+)
+
+// Analysis holds FFT, levels, onset strength and loudness of the whole track sampled at fixed rate
+type Analysis struct {
+	frames int
+
+	// time offset of the first frame in ms, frames are centered on their window
+	offset float64
+
+	fft      []float32
+	left     []float32
+	right    []float32
+	onset    []float32
+	loudness []float32
+}
+
+// NewAnalysis decodes the track once and analyses it, returns nil if file can't be decoded
+func NewAnalysis(path string) *Analysis {
+	return newAnalysis(func(flags C.DWORD) C.HSTREAM {
+		return C.CreateBassStream(C.CString(path), flags)
+	})
+}
+
+func newAnalysis(createStream func(flags C.DWORD) C.HSTREAM) *Analysis {
+	channel := createStream(C.BASS_STREAM_DECODE | C.BASS_SAMPLE_FLOAT | C.BASS_STREAM_PRESCAN)
+	if channel == 0 {
+		log.Println("Failed to decode track for analysis:", GetError())
+		return nil
+	}
+
+	defer C.BASS_StreamFree(channel)
+
+	var info C.BASS_CHANNELINFO
+	C.BASS_ChannelGetInfo(channel, &info)
+
+	channels := int(info.chans)
+	sampleRate := int(info.freq)
+
+	if channels == 0 || sampleRate == 0 {
+		return nil
+	}
+
+	hop := sampleRate / analysisFPS
+
+	analysis := &Analysis{
+		offset: (float64(hop) - analysisWindow/2) * 1000 / float64(sampleRate),
+	}
+
+	window := hannWindow(analysisWindow)
+	spectrum := make([]complex128, analysisWindow)
+
+	ring := make([]float64, analysisWindow)
+	ringPos := 0
+
+	previous := make([]float32, analysisBins)
+	maxFlux := 0.0
+
+	buffer := make([]float32, hop*channels)
+
+	for {
+		read := int(int32(C.BASS_ChannelGetData(channel, unsafe.Pointer(&buffer[0]), C.DWORD(len(buffer)*4))))
+		if read <= 0 {
+			break
+		}
+
+		samples := buffer[:read/4]
+
+		var peakL, peakR, sum float64
+
+		for i := 0; i+channels <= len(samples); i += channels {
+			l := float64(samples[i])
+			r := l
+
+			if channels > 1 {
+				r = float64(samples[i+1])
+			}
+
+			peakL = math.Max(peakL, math.Abs(l))
+			peakR = math.Max(peakR, math.Abs(r))
+
+			mono := (l + r) / 2
+			sum += mono * mono
+
+			ring[ringPos] = mono
+			ringPos = (ringPos + 1) % analysisWindow
+		}
+
+		for i := range spectrum {
+			spectrum[i] = complex(ring[(ringPos+i)%analysisWindow]*window[i], 0)
+		}
+
+		fft(spectrum)
+
+		flux := 0.0
+
+		for i := 0; i < analysisBins; i++ {
+			mag := float32(math.Hypot(real(spectrum[i]), imag(spectrum[i])))
+
+			if mag > previous[i] {
+				flux += float64(mag - previous[i])
+			}
+
+			previous[i] = mag
+		}
+
+		analysis.fft = append(analysis.fft, previous...)
+
+		analysis.left = append(analysis.left, float32(math.Min(peakL, 1)))
+		analysis.right = append(analysis.right, float32(math.Min(peakR, 1)))
+		analysis.onset = append(analysis.onset, float32(flux))
+
+		rms := math.Sqrt(sum / float64(len(samples)/channels))
+		analysis.loudness = append(analysis.loudness, float32(20*math.Log10(math.Max(rms, 1e-5))))
+
+		maxFlux = math.Max(maxFlux, flux)
+
+		analysis.frames++
+	}
+
+	if analysis.frames == 0 {
+		return nil
+	}
+
+	if maxFlux > 0 {
+		for i := range analysis.onset {
+			analysis.onset[i] /= float32(maxFlux)
+		}
+	}
+
+	log.Println("Track analysed,", analysis.frames, "frames")
+
+	return analysis
+}
+
+// frame returns indices of two frames surrounding given time in ms and interpolation factor between them
+func (analysis *Analysis) frame(time float64) (int, int, float32) {
+	pos := math.Max(0, (time-analysis.offset)*analysisFPS/1000)
+
+	i := int(pos)
+	if i >= analysis.frames-1 {
+		return analysis.frames - 1, analysis.frames - 1, 0
+	}
+
+	return i, i + 1, float32(pos - float64(i))
+}
+
+func (analysis *Analysis) lerp(values []float32, time float64) float64 {
+	i, j, t := analysis.frame(time)
+	return float64(values[i]*(1-t) + values[j]*t)
+}
+
+// GetFFT fills out with magnitudes at given time, bins above analysed range are zeroed
+func (analysis *Analysis) GetFFT(time float64, out []float32) {
+	i, j, t := analysis.frame(time)
+
+	a := analysis.fft[i*analysisBins : (i+1)*analysisBins]
+	b := analysis.fft[j*analysisBins : (j+1)*analysisBins]
+
+	for k := range out {
+		if k < analysisBins {
+			out[k] = a[k]*(1-t) + b[k]*t
+		} else {
+			out[k] = 0
+		}
+	}
+}
+
+func (analysis *Analysis) GetLevels(time float64) (float64, float64) {
+	return analysis.lerp(analysis.left, time), analysis.lerp(analysis.right, time)
+}
+
+// GetOnset returns onset strength in 0-1 range, normalized to the strongest onset in track
+func (analysis *Analysis) GetOnset(time float64) float64 {
+	return analysis.lerp(analysis.onset, time)
+}
+
+// GetLoudness returns RMS loudness in dBFS
+func (analysis *Analysis) GetLoudness(time float64) float64 {
+	return analysis.lerp(analysis.loudness, time)
+}
@@ -78,6 +78,10 @@ HSTREAM CreateBassStream(char *file, DWORD flags) {
 #endif
 }
 
+HSTREAM CreateBassStreamMem(void *data, QWORD length, DWORD flags) {
+	return BASS_StreamCreateFile(1, data, 0, length, flags);
+}
+
 HSAMPLE LoadBassSample(char *file, DWORD max, DWORD flags) {
 #ifdef _WIN32
 	wchar_t *wFile = convert(file);
@@ -3,6 +3,7 @@
 #include "bass.h"
 
 HSTREAM CreateBassStream(char* file, DWORD flags);
+HSTREAM CreateBassStreamMem(void* data, QWORD length, DWORD flags);
 HSAMPLE LoadBassSample(char* file, DWORD max, DWORD flags);
 
 #endif
\ No newline at end of file
@@ -0,0 +1,30 @@
+package bass
+
+import "github.com/tsunyoku/danser/app/settings"
+
+// Bus groups channels that are mixed and processed together
+type Bus int
+
+const (
+	BusMusic Bus = iota
+	BusHitsounds
+	BusSliderLoops
+	BusStoryboard
+	BusUI
+	busCount
+)
+
+var busNames = [busCount]string{"Music", "Hitsounds", "SliderLoops", "Storyboard", "UI"}
+
+func (bus Bus) String() string {
+	if bus < 0 || bus >= busCount {
+		return "Master"
+	}
+
+	return busNames[bus]
+}
+
+// gain returns the volume multiplier of the bus, used directly in live playback
+func (bus Bus) gain() float64 {
+	return settings.Audio.Mixer.GetBus(bus.String()).Gain
+}
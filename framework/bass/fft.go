@@ -0,0 +1,60 @@
+package bass
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// fft performs in-place iterative radix-2 FFT, len(data) has to be a power of 2
+func fft(data []complex128) {
+	n := len(data)
+
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+
+		j ^= bit
+
+		if i < j {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+
+	for length := 2; length <= n; length <<= 1 {
+		w := cmplx.Exp(complex(0, -2*math.Pi/float64(length)))
+
+		for i := 0; i < n; i += length {
+			wn := complex(1, 0)
+
+			for j := 0; j < length/2; j++ {
+				u := data[i+j]
+				v := data[i+j+length/2] * wn
+
+				data[i+j] = u + v
+				data[i+j+length/2] = u - v
+
+				wn *= w
+			}
+		}
+	}
+}
+
+// hannWindow returns Hann window coefficients normalized so a full scale sine peaks at 1 after fft
+func hannWindow(size int) []float64 {
+	window := make([]float64, size)
+
+	sum := 0.0
+
+	for i := range window {
+		window[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(size-1)))
+		sum += window[i]
+	}
+
+	for i := range window {
+		window[i] *= 2 / sum
+	}
+
+	return window
+}
@@ -0,0 +1,158 @@
+package bass
+
+import "math"
+
+// K-weighting filter coefficients for 48kHz as specified by ITU-R BS.1770-4
+var (
+	shelfB = [3]float64{1.53512485958697, -2.69169618940638, 1.19839281085285}
+	shelfA = [3]float64{1, -1.69065929318241, 0.73248077421585}
+
+	highPassB = [3]float64{1.0, -2.0, 1.0}
+	highPassA = [3]float64{1, -1.99004745483398, 0.99007225036621}
+)
+
+const (
+	absoluteGate = -70.0
+	relativeGate = -10.0
+)
+
+type biquad struct {
+	b, a   [3]float64
+	x1, x2 float64
+	y1, y2 float64
+}
+
+func (f *biquad) process(x float64) float64 {
+	y := f.b[0]*x + f.b[1]*f.x1 + f.b[2]*f.x2 - f.a[1]*f.y1 - f.a[2]*f.y2
+
+	f.x2, f.x1 = f.x1, x
+	f.y2, f.y1 = f.y1, y
+
+	return y
+}
+
+// loudnessMeter measures integrated loudness of a stereo 48kHz signal according to EBU R128
+type loudnessMeter struct {
+	filters [2][2]biquad
+
+	subBlockSize int
+	subBlockPos  int
+	subBlockSum  float64
+
+	// mean square energies of consecutive 100ms sub-blocks
+	subBlocks []float64
+}
+
+func newLoudnessMeter(sampleRate int) *loudnessMeter {
+	meter := &loudnessMeter{
+		subBlockSize: sampleRate / 10,
+	}
+
+	for i := range meter.filters {
+		meter.filters[i][0] = biquad{b: shelfB, a: shelfA}
+		meter.filters[i][1] = biquad{b: highPassB, a: highPassA}
+	}
+
+	return meter
+}
+
+// process accepts interleaved stereo samples
+func (meter *loudnessMeter) process(samples []float32) {
+	for i := 0; i+1 < len(samples); i += 2 {
+		for c := 0; c < 2; c++ {
+			z := meter.filters[c][1].process(meter.filters[c][0].process(float64(samples[i+c])))
+			meter.subBlockSum += z * z
+		}
+
+		meter.subBlockPos++
+
+		if meter.subBlockPos == meter.subBlockSize {
+			meter.subBlocks = append(meter.subBlocks, meter.subBlockSum/float64(meter.subBlockSize))
+			meter.subBlockPos = 0
+			meter.subBlockSum = 0
+		}
+	}
+}
+
+// integrated returns gated loudness in LUFS, -Inf if signal is silent
+func (meter *loudnessMeter) integrated() float64 {
+	var blocks []float64
+
+	// 400ms blocks with 75% overlap
+	for i := 0; i+4 <= len(meter.subBlocks); i++ {
+		energy := (meter.subBlocks[i] + meter.subBlocks[i+1] + meter.subBlocks[i+2] + meter.subBlocks[i+3]) / 4
+
+		if energyToLoudness(energy) > absoluteGate {
+			blocks = append(blocks, energy)
+		}
+	}
+
+	if len(blocks) == 0 {
+		return math.Inf(-1)
+	}
+
+	threshold := energyToLoudness(mean(blocks)) + relativeGate
+
+	var gated []float64
+
+	for _, e := range blocks {
+		if energyToLoudness(e) > threshold {
+			gated = append(gated, e)
+		}
+	}
+
+	if len(gated) == 0 {
+		return math.Inf(-1)
+	}
+
+	return energyToLoudness(mean(gated))
+}
+
+func energyToLoudness(energy float64) float64 {
+	return -0.691 + 10*math.Log10(energy)
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
+
+// limiter is a brickwall peak limiter with instant attack and exponential release
+type limiter struct {
+	ceiling float64
+	release float64
+	gain    float64
+}
+
+func newLimiter(ceilingDB float64, sampleRate int) *limiter {
+	return &limiter{
+		ceiling: math.Pow(10, ceilingDB/20),
+		release: 1 - math.Exp(-1/(0.05*float64(sampleRate))),
+		gain:    1,
+	}
+}
+
+// process accepts interleaved stereo samples
+func (l *limiter) process(samples []float32) {
+	for i := 0; i+1 < len(samples); i += 2 {
+		peak := math.Max(math.Abs(float64(samples[i])), math.Abs(float64(samples[i+1])))
+
+		target := 1.0
+		if peak > l.ceiling {
+			target = l.ceiling / peak
+		}
+
+		if target < l.gain {
+			l.gain = target
+		} else {
+			l.gain += (target - l.gain) * l.release
+		}
+
+		samples[i] *= float32(l.gain)
+		samples[i+1] *= float32(l.gain)
+	}
+}
@@ -20,18 +20,39 @@ static inline void SetSync(HSTREAM stream, QWORD pos, int eNum) {
 */
 import "C"
 import (
+	"bufio"
+	"encoding/binary"
 	"fmt"
+	"github.com/tsunyoku/danser/app/settings"
+	"io"
 	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
 	"unsafe"
 )
 
+const (
+	mixSampleRate = 48000
+
+	// 64 stereo frames, keeps syncs of all buses close to each other
+	mixChunkFrames = 64
+
+	processChunkFrames = 4096
+
+	// Max time after the last event to wait for looping channels to stop
+	maxTail = 30000.0
+)
+
 var GlobalTimeMs = 0.0
 var Offscreen = false
 
-var mixStream C.HSTREAM
+var busStreams [busCount]C.HSTREAM
 
 type trackEvent struct {
 	channel  C.DWORD
+	bus      Bus
 	time     float64
 	play     bool
 	delegate func() C.DWORD
@@ -39,14 +60,22 @@ type trackEvent struct {
 }
 
 var trackEvents = make([]trackEvent, 0)
+var calledEvents int
 
-func addNormalEvent(delegate func()) {
-	addDelayedEvent(0, delegate)
+// Stem is a single bus saved to a separate file
+type Stem struct {
+	Name string
+	Path string
 }
 
-func addDelayedEvent(delay float64, delegate func()) {
+func addNormalEvent(bus Bus, delegate func()) {
+	addDelayedEvent(bus, 0, delegate)
+}
+
+func addDelayedEvent(bus Bus, delay float64, delegate func()) {
 	trackEvents = append(trackEvents, trackEvent{
 		channel: 0,
+		bus:     bus,
 		time:    GlobalTimeMs + delay,
 		play:    false,
 		delegate: func() C.DWORD {
@@ -56,15 +85,23 @@ func addDelayedEvent(delay float64, delegate func()) {
 	})
 }
 
-func SaveToFile(file string) {
-	mixStream = C.BASS_Mixer_StreamCreate(48000, 2, C.BASS_STREAM_DECODE|C.BASS_MIXER_END|C.BASS_SAMPLE_FLOAT)
+// SaveToFile mixes all recorded events to a WAV file.
+// Every bus is rendered separately, normalised and limited according to settings.Audio.Mixer.
+// If settings.Recording.ExportStems is enabled, processed buses are additionally saved next to the file and returned.
+func SaveToFile(file string) []Stem {
+	for i := range busStreams {
+		busStreams[i] = C.BASS_Mixer_StreamCreate(mixSampleRate, 2, C.BASS_STREAM_DECODE|C.BASS_MIXER_NONSTOP|C.BASS_SAMPLE_FLOAT)
+	}
 
-	log.Println("Audio mixing stream created, adding events for processing...")
+	log.Println("Audio mixing streams created, adding events for processing...")
 
 	wasPlay := false
+	lastEvent := 0.0
 
 	for i, e := range trackEvents {
-		//Push music event early to prevent mix stream from closing too early (BASS_MIXER_END flag)
+		lastEvent = math.Max(lastEvent, e.time)
+
+		//Push music event early to prevent bus from being empty at the beginning
 		if e.play && e.channel != 0 && !wasPlay {
 			goCallback(C.int(i))
 
@@ -73,24 +110,360 @@ func SaveToFile(file string) {
 			continue
 		}
 
-		pos := C.BASS_ChannelSeconds2Bytes(mixStream, C.double(e.time/1000)) // get start position in bytes
-		C.SetSync(mixStream, pos, C.int(i))
-	}
+		stream := busStreams[e.bus]
 
-	log.Println("Events added, starting encoding audio...")
+		pos := C.BASS_ChannelSeconds2Bytes(stream, C.double(e.time/1000)) // get start position in bytes
+		C.SetSync(stream, pos, C.int(i))
+	}
 
-	C.BASS_Encode_Start(mixStream, C.CString(file), C.BASS_ENCODE_PCM, (*C.ENCODEPROC)(nil), unsafe.Pointer(nil)) // set a WAV writer on the mixer
+	log.Println("Events added, starting mixing audio...")
 
-	buffer := make([]byte, 512)
+	base := strings.TrimSuffix(file, filepath.Ext(file))
 
-	var ret int32
-	for ret != -1 {
-		ret = int32(C.BASS_ChannelGetData(mixStream, unsafe.Pointer(&buffer[0]), C.DWORD(len(buffer)))) // process the mixer
+	busFiles, meters, err := renderBuses(base, lastEvent)
+	if err != nil {
+		panic(err)
 	}
 
-	C.BASS_Encode_Stop(mixStream) // close the WAV writer
+	defer func() {
+		for _, f := range busFiles {
+			_ = os.Remove(f)
+		}
+	}()
+
+	log.Println("Buses rendered, processing...")
+
+	stems, err := processBuses(file, base, busFiles, meters)
+	if err != nil {
+		panic(err)
+	}
 
 	log.Println("Encoding finished!")
+
+	return stems
+}
+
+// renderBuses decodes all bus mixers in lockstep and saves their raw output to temporary files
+func renderBuses(base string, lastEvent float64) (files []string, meters []*loudnessMeter, err error) {
+	writers := make([]*wavWriter, busCount)
+
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		for _, w := range writers {
+			if w != nil {
+				w.discard()
+			}
+		}
+
+		files, meters = nil, nil
+	}()
+
+	for i := Bus(0); i < busCount; i++ {
+		path := base + ".bus-" + i.String() + ".tmp"
+
+		writers[i], err = newWavWriter(path, mixSampleRate)
+		if err != nil {
+			return
+		}
+
+		files = append(files, path)
+		meters = append(meters, newLoudnessMeter(mixSampleRate))
+	}
+
+	buffer := make([]float32, mixChunkFrames*2)
+
+	processed := 0
+
+	for {
+		for i, stream := range busStreams {
+			ret := C.BASS_ChannelGetData(stream, unsafe.Pointer(&buffer[0]), C.DWORD(len(buffer)*4))
+			if ret == C.DWORD(0xFFFFFFFF) {
+				errCode := GetError()
+				return nil, nil, fmt.Errorf("failed to render %s bus: %d (%s)", Bus(i).String(), errCode, errCode.Message())
+			}
+
+			// short reads are padded with silence so buses stay in lockstep and old data isn't written again
+			for j := int(ret) / 4; j < len(buffer); j++ {
+				buffer[j] = 0
+			}
+
+			meters[i].process(buffer)
+
+			if err = writers[i].write(buffer); err != nil {
+				return
+			}
+		}
+
+		processed += mixChunkFrames
+
+		timeMs := float64(processed) / mixSampleRate * 1000
+
+		if (calledEvents == len(trackEvents) && !hasActiveChannels()) || timeMs > lastEvent+maxTail {
+			break
+		}
+	}
+
+	for _, w := range writers {
+		if err = w.close(); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func hasActiveChannels() bool {
+	for _, stream := range busStreams {
+		if C.BASS_Mixer_StreamGetChannels(stream, nil, 0) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// processBuses applies gain, loudness normalisation and limiting to each bus, then mixes them into the final file
+func processBuses(file, base string, busFiles []string, meters []*loudnessMeter) (stems []Stem, err error) {
+	readers := make([]*wavReader, len(busFiles))
+	limiters := make([]*limiter, len(busFiles))
+	gains := make([]float32, len(busFiles))
+
+	var stemWriters []*wavWriter
+
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		for _, w := range stemWriters {
+			w.discard()
+		}
+
+		stems = nil
+	}()
+
+	for i := range busFiles {
+		bus := Bus(i)
+		busSettings := settings.Audio.Mixer.GetBus(bus.String())
+
+		gains[i] = float32(busSettings.Gain * normalisationGain(bus.String(), meters[i].integrated(), busSettings.LoudnessTarget))
+
+		if busSettings.Limiter {
+			limiters[i] = newLimiter(busSettings.LimiterCeiling, mixSampleRate)
+		}
+
+		if readers[i], err = openWavReader(busFiles[i]); err != nil {
+			return
+		}
+
+		defer readers[i].close()
+
+		if settings.Recording.ExportStems {
+			stem := Stem{Name: bus.String(), Path: base + "-" + bus.String() + ".wav"}
+
+			w, err2 := newWavWriter(stem.Path, mixSampleRate)
+			if err2 != nil {
+				err = err2
+				return
+			}
+
+			stems = append(stems, stem)
+			stemWriters = append(stemWriters, w)
+		}
+	}
+
+	masterPath := base + ".bus-Master.tmp"
+
+	masterWriter, err := newWavWriter(masterPath, mixSampleRate)
+	if err != nil {
+		return
+	}
+
+	defer masterWriter.discard()
+
+	masterMeter := newLoudnessMeter(mixSampleRate)
+
+	buffer := make([]float32, processChunkFrames*2)
+	mix := make([]float32, processChunkFrames*2)
+
+	for {
+		read := 0
+
+		for j := range mix {
+			mix[j] = 0
+		}
+
+		for i, reader := range readers {
+			n, err2 := reader.read(buffer)
+			if err2 != nil && err2 != io.EOF {
+				err = err2
+				return
+			}
+
+			chunk := buffer[:n]
+
+			for j := range chunk {
+				chunk[j] *= gains[i]
+			}
+
+			if limiters[i] != nil {
+				limiters[i].process(chunk)
+			}
+
+			if stemWriters != nil {
+				if err = stemWriters[i].write(chunk); err != nil {
+					return
+				}
+			}
+
+			for j, v := range chunk {
+				mix[j] += v
+			}
+
+			if n > read {
+				read = n
+			}
+		}
+
+		if read == 0 {
+			break
+		}
+
+		masterMeter.process(mix[:read])
+
+		if err = masterWriter.write(mix[:read]); err != nil {
+			return
+		}
+	}
+
+	for _, w := range stemWriters {
+		if err = w.close(); err != nil {
+			return
+		}
+	}
+
+	if err = masterWriter.close(); err != nil {
+		return
+	}
+
+	err = processMaster(file, masterPath, masterMeter, buffer)
+
+	return
+}
+
+func processMaster(file, masterPath string, meter *loudnessMeter, buffer []float32) error {
+	master := settings.Audio.Mixer.Master
+
+	gain := float32(master.Gain * normalisationGain("Master", meter.integrated(), master.LoudnessTarget))
+
+	var lim *limiter
+	if master.Limiter {
+		lim = newLimiter(master.LimiterCeiling, mixSampleRate)
+	}
+
+	reader, err := openWavReader(masterPath)
+	if err != nil {
+		return err
+	}
+
+	defer reader.close()
+
+	writer, err := newWavWriter(file, mixSampleRate)
+	if err != nil {
+		return err
+	}
+
+	for {
+		n, err := reader.read(buffer)
+		if err != nil && err != io.EOF {
+			writer.close()
+			return err
+		}
+
+		if n == 0 {
+			break
+		}
+
+		chunk := buffer[:n]
+
+		for j := range chunk {
+			chunk[j] *= gain
+		}
+
+		if lim != nil {
+			lim.process(chunk)
+		}
+
+		if err = writer.write(chunk); err != nil {
+			writer.close()
+			return err
+		}
+	}
+
+	return writer.close()
+}
+
+func normalisationGain(name string, loudness, target float64) float64 {
+	if target == 0 || math.IsInf(loudness, -1) {
+		return 1
+	}
+
+	log.Println(fmt.Sprintf("%s bus loudness: %.2f LUFS, normalising to %.2f LUFS", name, loudness, target))
+
+	return math.Pow(10, (target-loudness)/20)
+}
+
+type wavReader struct {
+	file   *os.File
+	reader *bufio.Reader
+	buffer []byte
+}
+
+func openWavReader(path string) (*wavReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &wavReader{
+		file:   file,
+		reader: bufio.NewReader(file),
+	}
+
+	// Skip the header written by wavWriter
+	if _, err = r.reader.Discard(44); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// read fills samples and returns how many were read
+func (r *wavReader) read(samples []float32) (int, error) {
+	if cap(r.buffer) < len(samples)*4 {
+		r.buffer = make([]byte, len(samples)*4)
+	}
+
+	n, err := io.ReadFull(r.reader, r.buffer[:len(samples)*4])
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+
+	count := n / 4
+
+	for i := 0; i < count; i++ {
+		samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(r.buffer[i*4:]))
+	}
+
+	return count, err
+}
+
+func (r *wavReader) close() {
+	_ = r.file.Close()
 }
 
 //export goCallback
@@ -119,6 +492,8 @@ func goCallback(i C.int) {
 func processEvent(eventIndex int) {
 	event := trackEvents[eventIndex]
 
+	stream := busStreams[event.bus]
+
 	var ret C.DWORD
 	if event.delegate != nil {
 		ret = event.delegate()
@@ -126,10 +501,10 @@ func processEvent(eventIndex int) {
 
 	if event.play {
 		if ret != 0 { //add samples to the queue
-			C.BASS_Mixer_StreamAddChannel(mixStream, ret, C.BASS_STREAM_AUTOFREE|C.BASS_MIXER_CHAN_NORAMPIN)
+			C.BASS_Mixer_StreamAddChannel(stream, ret, C.BASS_STREAM_AUTOFREE|C.BASS_MIXER_CHAN_NORAMPIN)
 		} else { //push main music to the queue
-			pos := C.BASS_ChannelSeconds2Bytes(mixStream, C.double(event.time/1000))
-			C.BASS_Mixer_StreamAddChannelEx(mixStream, event.channel, C.BASS_STREAM_AUTOFREE|C.BASS_MIXER_CHAN_NORAMPIN, pos, C.QWORD(0))
+			pos := C.BASS_ChannelSeconds2Bytes(stream, C.double(event.time/1000))
+			C.BASS_Mixer_StreamAddChannelEx(stream, event.channel, C.BASS_STREAM_AUTOFREE|C.BASS_MIXER_CHAN_NORAMPIN, pos, C.QWORD(0))
 		}
 	}
 
@@ -140,4 +515,6 @@ func processEvent(eventIndex int) {
 
 	event.called = true
 	trackEvents[eventIndex] = event
+
+	calledEvents++
 }
@@ -17,11 +17,13 @@ type SubSample struct {
 	bassSample C.DWORD
 	sampleChan C.HCHANNEL
 	streamChan C.HSTREAM
+	bus        Bus
 }
 
 type Sample struct {
 	bassSample C.DWORD
 	data       []byte
+	bus        Bus
 }
 
 var loopingStreams = make(map[*SubSample]int)
@@ -41,6 +43,7 @@ func NewSample(path string) *Sample {
 	defer f.Close()
 
 	sample := new(Sample)
+	sample.bus = BusHitsounds
 
 	sample.data, err = ioutil.ReadAll(f)
 	if err != nil {
@@ -59,14 +62,25 @@ func NewSampleData(data []byte) *Sample {
 
 	sample := new(Sample)
 	sample.data = data
+	sample.bus = BusHitsounds
 	sample.bassSample = C.BASS_SampleLoad(1, unsafe.Pointer(&data[0]), 0, C.DWORD(len(data)), 32, C.BASS_SAMPLE_OVER_POS)
 
 	return sample
 }
 
+// SetBus sets the mixer bus the sample will be played on
+func (sample *Sample) SetBus(bus Bus) {
+	sample.bus = bus
+}
+
+func (sample *Sample) GetBus() Bus {
+	return sample.bus
+}
+
 func (sample *Sample) Play() *SubSample {
 	sub := new(SubSample)
 	sub.bassSample = sample.bassSample
+	sub.bus = sample.bus
 
 	if sample.bassSample == 0 {
 		return sub
@@ -74,7 +88,7 @@ func (sample *Sample) Play() *SubSample {
 
 	if !Offscreen {
 		channel := C.BASS_SampleGetChannel(C.DWORD(sample.bassSample), 0)
-		C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_VOL, C.float(settings.Audio.GeneralVolume*settings.Audio.SampleVolume))
+		C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_VOL, C.float(settings.Audio.GeneralVolume*settings.Audio.SampleVolume*sample.bus.gain()))
 		C.BASS_ChannelPlay(channel, 1)
 
 		sub.sampleChan = channel
@@ -100,6 +114,7 @@ func (sample *Sample) PlayLoop() *SubSample {
 func (sample *Sample) PlayV(volume float64) *SubSample {
 	sub := new(SubSample)
 	sub.bassSample = sample.bassSample
+	sub.bus = sample.bus
 
 	if sample.bassSample == 0 {
 		return sub
@@ -107,7 +122,7 @@ func (sample *Sample) PlayV(volume float64) *SubSample {
 
 	if !Offscreen {
 		channel := C.BASS_SampleGetChannel(C.DWORD(sample.bassSample), 0)
-		C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_VOL, C.float(volume))
+		C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_VOL, C.float(volume*sample.bus.gain()))
 		C.BASS_ChannelPlay(channel, 1)
 
 		sub.sampleChan = channel
@@ -133,6 +148,7 @@ func (sample *Sample) PlayVLoop(volume float64) *SubSample {
 func (sample *Sample) PlayRV(volume float64) *SubSample {
 	sub := new(SubSample)
 	sub.bassSample = sample.bassSample
+	sub.bus = sample.bus
 
 	if sample.bassSample == 0 {
 		return sub
@@ -140,7 +156,7 @@ func (sample *Sample) PlayRV(volume float64) *SubSample {
 
 	if !Offscreen {
 		channel := C.BASS_SampleGetChannel(C.DWORD(sample.bassSample), 0)
-		C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_VOL, C.float(settings.Audio.GeneralVolume*settings.Audio.SampleVolume*volume))
+		C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_VOL, C.float(settings.Audio.GeneralVolume*settings.Audio.SampleVolume*volume*sample.bus.gain()))
 		C.BASS_ChannelPlay(channel, 1)
 
 		sub.sampleChan = channel
@@ -166,6 +182,7 @@ func (sample *Sample) PlayRVLoop(volume float64) *SubSample {
 func (sample *Sample) PlayRVPos(volume float64, balance float64) *SubSample {
 	sub := new(SubSample)
 	sub.bassSample = sample.bassSample
+	sub.bus = sample.bus
 
 	if sample.bassSample == 0 {
 		return sub
@@ -173,7 +190,7 @@ func (sample *Sample) PlayRVPos(volume float64, balance float64) *SubSample {
 
 	if !Offscreen {
 		channel := C.BASS_SampleGetChannel(C.DWORD(sample.bassSample), 0)
-		C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_VOL, C.float(settings.Audio.GeneralVolume*settings.Audio.SampleVolume*volume))
+		C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_VOL, C.float(settings.Audio.GeneralVolume*settings.Audio.SampleVolume*volume*sample.bus.gain()))
 		C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_PAN, C.float(balance))
 		C.BASS_ChannelPlay(channel, 1)
 
@@ -201,6 +218,7 @@ func (sample *Sample) PlayRVPosLoop(volume float64, balance float64) *SubSample
 func (sample *Sample) createPlayEvent(sSample *SubSample, delegate func()) {
 	trackEvents = append(trackEvents, trackEvent{
 		channel: 0,
+		bus:     sample.bus,
 		time:    GlobalTimeMs,
 		play:    true,
 		delegate: func() C.DWORD {
@@ -228,7 +246,7 @@ func setLoop(sSample *SubSample) {
 		return
 	}
 
-	addNormalEvent(func() {
+	addNormalEvent(sSample.bus, func() {
 		if sSample.streamChan != 0 {
 			C.BASS_ChannelFlags(sSample.streamChan, C.BASS_SAMPLE_LOOP, C.BASS_SAMPLE_LOOP)
 		}
@@ -246,7 +264,7 @@ func SetRate(channel *SubSample, rate float64) {
 		return
 	}
 
-	addNormalEvent(func() {
+	addNormalEvent(channel.bus, func() {
 		C.BASS_ChannelSetAttribute(C.HCHANNEL(channel.streamChan), C.BASS_ATTRIB_FREQ, C.float(rate))
 	})
 }
@@ -264,7 +282,7 @@ func StopSample(channel *SubSample) {
 		return
 	}
 
-	addNormalEvent(func() {
+	addNormalEvent(channel.bus, func() {
 		C.BASS_ChannelStop(C.HCHANNEL(channel.streamChan))
 	})
 }
@@ -280,7 +298,7 @@ func PauseSample(channel *SubSample) {
 		return
 	}
 
-	addNormalEvent(func() {
+	addNormalEvent(channel.bus, func() {
 		C.BASS_ChannelPause(C.HCHANNEL(channel.streamChan))
 	})
 }
@@ -296,7 +314,7 @@ func PlaySample(channel *SubSample) {
 		return
 	}
 
-	addNormalEvent(func() {
+	addNormalEvent(channel.bus, func() {
 		C.BASS_ChannelPlay(C.HCHANNEL(channel.streamChan), 0)
 	})
 }
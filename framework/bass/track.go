@@ -1,6 +1,7 @@
 package bass
 
 /*
+#include <stdlib.h>
 #include "bass_util.h"
 #include "bass.h"
 #include "bass_fx.h"
@@ -12,6 +13,7 @@ import (
 	"unsafe"
 	//"log"
 	"math"
+	"sync"
 )
 
 const (
@@ -34,16 +36,57 @@ type Track struct {
 	speed            float64
 	pitch            float64
 	playing          bool
+
+	analysis      *Analysis
+	analysisReady chan *Analysis
+	analysing     sync.WaitGroup
+
+	data unsafe.Pointer
 }
 
 func NewTrack(path string) *Track {
+	return newTrack(func(flags C.DWORD) C.HSTREAM {
+		return C.CreateBassStream(C.CString(path), flags)
+	})
+}
+
+// NewTrackData creates a track from audio file contents, like a file read from an .osz archive.
+// Data is copied to C memory since BASS reads from it for the whole life of the stream, it's freed in Dispose.
+func NewTrackData(data []byte) *Track {
+	mem := C.CBytes(data)
+	length := C.QWORD(len(data))
+
+	player := newTrack(func(flags C.DWORD) C.HSTREAM {
+		return C.CreateBassStreamMem(mem, length, flags)
+	})
+
+	player.data = mem
+
+	return player
+}
+
+func newTrack(createStream func(flags C.DWORD) C.HSTREAM) *Track {
 	player := new(Track)
 	player.speed = 1
 	player.pitch = 1
 	player.lastVol = -100000
 	player.fft = make([]float32, 512)
+	player.analysisReady = make(chan *Analysis, 1)
+
+	player.analysing.Add(1)
+
+	if Offscreen {
+		// Recording has to be deterministic so we wait for analysis
+		player.analysisReady <- newAnalysis(createStream)
+		player.analysing.Done()
+	} else {
+		go func() {
+			player.analysisReady <- newAnalysis(createStream)
+			player.analysing.Done()
+		}()
+	}
 
-	player.channel = C.CreateBassStream(C.CString(path), C.BASS_ASYNCFILE|C.BASS_STREAM_DECODE|C.BASS_STREAM_PRESCAN)
+	player.channel = createStream(C.BASS_ASYNCFILE | C.BASS_STREAM_DECODE | C.BASS_STREAM_PRESCAN)
 	if !Offscreen {
 		player.channel = C.BASS_FX_TempoCreate(player.channel, C.BASS_FX_FREESOURCE)
 		setupFXChannel(player.channel)
@@ -51,7 +94,7 @@ func NewTrack(path string) *Track {
 		return player
 	}
 
-	second := C.CreateBassStream(C.CString(path), C.BASS_STREAM_DECODE|C.BASS_STREAM_PRESCAN)
+	second := createStream(C.BASS_STREAM_DECODE | C.BASS_STREAM_PRESCAN)
 	player.offscreenChannel = C.BASS_FX_TempoCreate(second, C.BASS_STREAM_DECODE|C.BASS_FX_FREESOURCE)
 
 	setupFXChannel(player.offscreenChannel)
@@ -59,6 +102,25 @@ func NewTrack(path string) *Track {
 	return player
 }
 
+// Dispose frees streams of the track and the audio data it was created from, track can't be used afterwards
+func (wv *Track) Dispose() {
+	wv.playing = false
+
+	// analysis stream reads the same data so it has to finish first
+	wv.analysing.Wait()
+
+	C.BASS_StreamFree(wv.channel)
+
+	if wv.offscreenChannel != 0 {
+		C.BASS_StreamFree(wv.offscreenChannel)
+	}
+
+	if wv.data != nil {
+		C.free(wv.data)
+		wv.data = nil
+	}
+}
+
 func setupFXChannel(channel C.HSTREAM) {
 	C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_TEMPO_OPTION_USE_QUICKALGO, 1)
 	C.BASS_ChannelSetAttribute(channel, C.BASS_ATTRIB_TEMPO_OPTION_OVERLAP_MS, C.float(4.0))
@@ -78,6 +140,7 @@ func (wv *Track) Play() {
 
 	trackEvents = append(trackEvents, trackEvent{
 		channel:  wv.offscreenChannel,
+		bus:      BusMusic,
 		time:     GlobalTimeMs,
 		play:     true,
 		delegate: nil,
@@ -97,6 +160,7 @@ func (wv *Track) PlayV(volume float64) {
 
 	trackEvents = append(trackEvents, trackEvent{
 		channel:  wv.offscreenChannel,
+		bus:      BusMusic,
 		time:     GlobalTimeMs,
 		play:     true,
 		delegate: nil,
@@ -112,7 +176,7 @@ func (wv *Track) Pause() {
 		return
 	}
 
-	addNormalEvent(func() {
+	addNormalEvent(BusMusic, func() {
 		C.BASS_ChannelPause(C.DWORD(wv.offscreenChannel))
 	})
 }
@@ -126,7 +190,7 @@ func (wv *Track) Resume() {
 		return
 	}
 
-	addNormalEvent(func() {
+	addNormalEvent(BusMusic, func() {
 		C.BASS_ChannelPlay(C.DWORD(wv.offscreenChannel), 0)
 	})
 }
@@ -140,20 +204,20 @@ func (wv *Track) Stop() {
 		return
 	}
 
-	addNormalEvent(func() {
+	addNormalEvent(BusMusic, func() {
 		C.BASS_ChannelStop(C.DWORD(wv.offscreenChannel))
 	})
 }
 
 func (wv *Track) SetVolume(vol float64) {
 	if !Offscreen {
-		C.BASS_ChannelSetAttribute(C.DWORD(wv.channel), C.BASS_ATTRIB_VOL, C.float(vol))
+		C.BASS_ChannelSetAttribute(C.DWORD(wv.channel), C.BASS_ATTRIB_VOL, C.float(vol*BusMusic.gain()))
 
 		return
 	}
 
 	if math.Abs(wv.lastVol-vol) > 0.001 {
-		addNormalEvent(func() {
+		addNormalEvent(BusMusic, func() {
 			C.BASS_ChannelSetAttribute(C.DWORD(wv.offscreenChannel), C.BASS_ATTRIB_VOL, C.float(vol))
 		})
 
@@ -164,13 +228,13 @@ func (wv *Track) SetVolume(vol float64) {
 func (wv *Track) SetVolumeRelative(vol float64) {
 	combined := settings.Audio.GeneralVolume * settings.Audio.MusicVolume * vol
 	if !Offscreen {
-		C.BASS_ChannelSetAttribute(C.DWORD(wv.channel), C.BASS_ATTRIB_VOL, C.float(combined))
+		C.BASS_ChannelSetAttribute(C.DWORD(wv.channel), C.BASS_ATTRIB_VOL, C.float(combined*BusMusic.gain()))
 
 		return
 	}
 
 	if math.Abs(wv.lastVol-combined) > 0.001 {
-		addNormalEvent(func() {
+		addNormalEvent(BusMusic, func() {
 			C.BASS_ChannelSetAttribute(C.DWORD(wv.offscreenChannel), C.BASS_ATTRIB_VOL, C.float(combined))
 		})
 
@@ -189,7 +253,7 @@ func (wv *Track) SetPosition(pos float64) {
 		return
 	}
 
-	addNormalEvent(func() {
+	addNormalEvent(BusMusic, func() {
 		C.BASS_ChannelSetPosition(wv.offscreenChannel, C.BASS_ChannelSeconds2Bytes(wv.offscreenChannel, C.double(pos /*+tMs/1000*/)), C.BASS_POS_BYTE|C.BASS_POS_DECODETO)
 	})
 }
@@ -215,7 +279,7 @@ func (wv *Track) SetTempo(tempo float64) {
 		return
 	}
 
-	addNormalEvent(func() {
+	addNormalEvent(BusMusic, func() {
 		C.BASS_ChannelSetAttribute(C.DWORD(wv.offscreenChannel), C.BASS_ATTRIB_TEMPO, C.float((tempo-1.0)*100))
 	})
 }
@@ -237,7 +301,7 @@ func (wv *Track) SetPitch(tempo float64) {
 		return
 	}
 
-	addNormalEvent(func() {
+	addNormalEvent(BusMusic, func() {
 		C.BASS_ChannelSetAttribute(C.DWORD(wv.offscreenChannel), C.BASS_ATTRIB_TEMPO_PITCH, C.float((tempo-1.0)*14.4))
 	})
 }
@@ -250,7 +314,19 @@ func (wv *Track) GetState() int {
 	return int(C.BASS_ChannelIsActive(wv.channel))
 }
 
-func (wv *Track) Update() {
+// Update refreshes FFT and levels at given track time in ms. Offline analysis is used once available,
+// live data is used before that
+func (wv *Track) Update(time float64) {
+	select {
+	case wv.analysis = <-wv.analysisReady:
+	default:
+	}
+
+	if wv.analysis != nil {
+		wv.updateAnalysed(time)
+		return
+	}
+
 	if wv.playing {
 		C.BASS_ChannelGetData(wv.channel, unsafe.Pointer(&wv.fft[0]), C.BASS_DATA_FFT1024)
 	} else {
@@ -259,6 +335,33 @@ func (wv *Track) Update() {
 		}
 	}
 
+	level := int(C.BASS_ChannelGetLevel(wv.channel))
+
+	left := level & 65535
+	right := level >> 16
+
+	wv.leftChannel = float64(left) / 32768
+	wv.rightChannel = float64(right) / 32768
+
+	wv.processFFT()
+}
+
+func (wv *Track) updateAnalysed(time float64) {
+	if wv.playing {
+		wv.analysis.GetFFT(time, wv.fft)
+		wv.leftChannel, wv.rightChannel = wv.analysis.GetLevels(time)
+	} else {
+		for i := range wv.fft {
+			wv.fft[i] = 0
+		}
+
+		wv.leftChannel, wv.rightChannel = 0, 0
+	}
+
+	wv.processFFT()
+}
+
+func (wv *Track) processFFT() {
 	toPeak := 0.0
 	beatAv := 0.0
 
@@ -281,14 +384,6 @@ func (wv *Track) Update() {
 	wv.lowMax = beatAv
 	wv.boost = boost
 	wv.peak = toPeak
-
-	level := int(C.BASS_ChannelGetLevel(wv.channel))
-
-	left := level & 65535
-	right := level >> 16
-
-	wv.leftChannel = float64(left) / 32768
-	wv.rightChannel = float64(right) / 32768
 }
 
 func (wv *Track) GetFFT() []float32 {
@@ -318,3 +413,8 @@ func (wv *Track) GetBoost() float64 {
 func (wv *Track) GetBeat() float64 {
 	return wv.lowMax
 }
+
+// GetAnalysis returns offline analysis of the track, nil if it's not ready yet
+func (wv *Track) GetAnalysis() *Analysis {
+	return wv.analysis
+}
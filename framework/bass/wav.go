@@ -0,0 +1,111 @@
+package bass
+
+import (
+	"bufio"
+	"encoding/binary"
+	"math"
+	"os"
+)
+
+// wavWriter writes interleaved 32-bit float stereo samples to a WAV file
+type wavWriter struct {
+	file   *os.File
+	writer *bufio.Writer
+
+	sampleRate int
+	dataSize   uint32
+	buffer     []byte
+}
+
+func newWavWriter(path string, sampleRate int) (*wavWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &wavWriter{
+		file:       file,
+		writer:     bufio.NewWriter(file),
+		sampleRate: sampleRate,
+	}
+
+	// Header is rewritten with proper sizes in close
+	if err = w.writeHeader(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *wavWriter) writeHeader() error {
+	header := make([]byte, 44)
+
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], 36+w.dataSize)
+	copy(header[8:], "WAVE")
+	copy(header[12:], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:], 16)
+	binary.LittleEndian.PutUint16(header[20:], 3) // IEEE float
+	binary.LittleEndian.PutUint16(header[22:], 2)
+	binary.LittleEndian.PutUint32(header[24:], uint32(w.sampleRate))
+	binary.LittleEndian.PutUint32(header[28:], uint32(w.sampleRate*2*4))
+	binary.LittleEndian.PutUint16(header[32:], 2*4)
+	binary.LittleEndian.PutUint16(header[34:], 32)
+	copy(header[36:], "data")
+	binary.LittleEndian.PutUint32(header[40:], w.dataSize)
+
+	_, err := w.writer.Write(header)
+
+	return err
+}
+
+func (w *wavWriter) write(samples []float32) error {
+	if cap(w.buffer) < len(samples)*4 {
+		w.buffer = make([]byte, len(samples)*4)
+	}
+
+	buf := w.buffer[:len(samples)*4]
+
+	for i, s := range samples {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(s))
+	}
+
+	w.dataSize += uint32(len(buf))
+
+	_, err := w.writer.Write(buf)
+
+	return err
+}
+
+// discard closes the file without finishing it and removes it from disk
+func (w *wavWriter) discard() {
+	_ = w.file.Close()
+	_ = os.Remove(w.file.Name())
+}
+
+func (w *wavWriter) close() error {
+	if err := w.writer.Flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+
+	if _, err := w.file.Seek(0, 0); err != nil {
+		w.file.Close()
+		return err
+	}
+
+	w.writer.Reset(w.file)
+
+	if err := w.writeHeader(); err != nil {
+		w.file.Close()
+		return err
+	}
+
+	if err := w.writer.Flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+
+	return w.file.Close()
+}
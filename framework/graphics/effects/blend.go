@@ -18,6 +18,7 @@ type Blend struct {
 	blendShader  *shader.RShader
 	vao          *buffer.VertexArrayObject
 	multiTexture *texture.TextureMultiLayer
+	weights      []float32
 }
 
 func NewBlend(width, height, frames int, weights []float32) *Blend {
@@ -62,14 +63,7 @@ func NewBlend(width, height, frames int, weights []float32) *Blend {
 
 	effect.blendShader.SetUniform("layers", frames)
 
-	var sum float32
-	for _, v := range weights {
-		sum += v
-	}
-
-	for i, v := range weights {
-		effect.blendShader.SetUniformArr("weights", i, v/sum)
-	}
+	effect.weights = weights
 
 	effect.multiTexture = texture.NewTextureMultiLayerFormat(width, height, texture.RGB, 0, frames)
 
@@ -92,10 +86,34 @@ func (effect *Blend) End() {
 	effect.fbos[effect.head].Unbind()
 }
 
+func (effect *Blend) GetLayers() int {
+	return effect.layers
+}
+
+// Blend blends all stored frames using weights given in NewBlend
 func (effect *Blend) Blend() {
+	effect.BlendWeighted(effect.weights)
+}
+
+// BlendWeighted blends len(weights) most recent frames, weights[0] is used for the oldest one
+func (effect *Blend) BlendWeighted(weights []float32) {
+	if len(weights) == 0 || len(weights) > effect.layers {
+		panic("Wrong number of weights")
+	}
+
+	var sum float32
+	for _, v := range weights {
+		sum += v
+	}
+
+	for i, v := range weights {
+		effect.blendShader.SetUniformArr("weights", i, v/sum)
+	}
+
 	effect.multiTexture.Bind(0)
 	effect.blendShader.SetUniform("tex", 0)
 	effect.blendShader.SetUniform("head", effect.head)
+	effect.blendShader.SetUniform("count", len(weights))
 
 	viewport.Push(effect.width, effect.height)
 
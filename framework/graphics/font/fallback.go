@@ -0,0 +1,95 @@
+package font
+
+import (
+	"github.com/tsunyoku/danser/framework/graphics/texture"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"io"
+	"io/ioutil"
+	"log"
+	"sync"
+)
+
+type fallbackFont struct {
+	ttf  *sfnt.Font
+	face font.Face
+	buff *sfnt.Buffer
+}
+
+var fallbackFonts []*fallbackFont
+
+// faces and buffers are not safe for concurrent use, it also guards glyph maps of fonts using fallbacks
+var fallbackMutex = &sync.Mutex{}
+
+// AddFallback loads TTF, OTF or TTC font which will be used, in order of adding, for characters missing in loaded TTF fonts
+func AddFallback(reader io.Reader) error {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	collection, err := opentype.ParseCollection(data)
+	if err != nil {
+		return err
+	}
+
+	fallbackMutex.Lock()
+	defer fallbackMutex.Unlock()
+
+	for i := 0; i < collection.NumFonts(); i++ {
+		ttf, err := collection.Font(i)
+		if err != nil {
+			return err
+		}
+
+		fc, err := opentype.NewFace(ttf, &opentype.FaceOptions{Size: 64, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return err
+		}
+
+		buff := &sfnt.Buffer{}
+
+		fallbackFonts = append(fallbackFonts, &fallbackFont{ttf, fc, buff})
+
+		name, _ := ttf.Name(buff, sfnt.NameIDFull)
+
+		log.Println(name, "loaded as fallback!")
+	}
+
+	return nil
+}
+
+func findFallback(c rune) *fallbackFont {
+	for _, fb := range fallbackFonts {
+		if idx, _ := fb.ttf.GlyphIndex(fb.buff, c); idx > 0 {
+			return fb
+		}
+	}
+
+	return nil
+}
+
+// measureFallback returns glyph data without a texture region, nil if none of the fallbacks has the character
+func measureFallback(c rune, ascent float64) *glyphData {
+	fb := findFallback(c)
+	if fb == nil {
+		return nil
+	}
+
+	b, gAdv, _, _ := measureGlyph(fb.ttf, fb.face, fb.buff, c)
+
+	return &glyphData{nil, float64(gAdv) / 64, float64(b.Min.X) / 64, ascent - float64(-b.Min.Y)/64}
+}
+
+// rasterizeFallback draws the character from the fallback that has it into the atlas, needs to be called on GL thread
+func rasterizeFallback(atlas *texture.TextureAtlas, c rune) *texture.TextureRegion {
+	fb := findFallback(c)
+	if fb == nil {
+		return nil
+	}
+
+	b, _, w, h := measureGlyph(fb.ttf, fb.face, fb.buff, c)
+
+	return rasterizeGlyph(atlas, fb.face, c, b, w, h)
+}
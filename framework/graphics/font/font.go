@@ -35,6 +35,55 @@ type Font struct {
 	Overlap     float64
 	ascent      float64
 	flip        bool
+	fallback    bool
+}
+
+// getGlyph returns glyph of the character, measuring it from fallback fonts if the font doesn't have it
+func (font *Font) getGlyph(c rune) *glyphData {
+	if !font.fallback {
+		return font.glyphs[c]
+	}
+
+	fallbackMutex.Lock()
+	defer fallbackMutex.Unlock()
+
+	char, exists := font.glyphs[c]
+	if !exists {
+		char = measureFallback(c, font.ascent)
+		font.glyphs[c] = char
+	}
+
+	return char
+}
+
+// rasterizeFallbacks puts fallback glyphs needed by the text in the atlas
+func (font *Font) rasterizeFallbacks(renderer *batch.QuadBatch, text string) {
+	if !font.fallback {
+		return
+	}
+
+	added := false
+
+	for _, c := range text {
+		char := font.getGlyph(c)
+		if char == nil || char.region != nil {
+			continue
+		}
+
+		if !added {
+			//atlas may get a new layer, so quads drawn before need to be flushed
+			renderer.Flush()
+			added = true
+		}
+
+		fallbackMutex.Lock()
+		char.region = rasterizeFallback(font.atlas, c)
+		fallbackMutex.Unlock()
+	}
+
+	if added {
+		font.atlas.GenerateMipmaps()
+	}
 }
 
 func (font *Font) drawInternal(renderer *batch.QuadBatch, x, y float64, size float64, text string, monospaced bool) {
@@ -48,9 +97,11 @@ func (font *Font) drawInternal(renderer *batch.QuadBatch, x, y float64, size flo
 
 	advance := 0.0
 
+	font.rasterizeFallbacks(renderer, text)
+
 	for i, c := range text {
-		char := font.glyphs[c]
-		if char == nil {
+		char := font.getGlyph(c)
+		if char == nil || char.region == nil {
 			continue
 		}
 
@@ -94,7 +145,7 @@ func (font *Font) getWidthInternal(size float64, text string, monospaced bool) f
 	scale := size / font.initialSize
 
 	for i, c := range text {
-		char := font.glyphs[c]
+		char := font.getGlyph(c)
 		if char == nil {
 			continue
 		}
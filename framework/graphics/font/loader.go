@@ -30,6 +30,7 @@ func LoadFont(reader io.Reader) *Font {
 
 	fnt := new(Font)
 	fnt.flip = true
+	fnt.fallback = true
 	fnt.initialSize = 64.0
 	fnt.glyphs = make(map[rune]*glyphData)
 	fnt.kernTable = make(map[rune]map[rune]float64)
@@ -48,45 +49,9 @@ func LoadFont(reader io.Reader) *Font {
 
 	for i := rune(0); i <= unicode.MaxRune; i++ {
 		if idx, _ := ttf.GlyphIndex(buff, i); idx > 0 {
-			b, gAdv, _ := fc.GlyphBounds(i)
-			w, h := (b.Max.X - b.Min.X).Ceil(), (b.Max.Y - b.Min.Y).Ceil()
+			b, gAdv, w, h := measureGlyph(ttf, fc, buff, i)
 
-			if w == 0 || h == 0 {
-				b, _ = ttf.Bounds(buff, fixed.Int26_6(20), font.HintingFull)
-
-				w, h = (b.Max.X - b.Min.X).Ceil(), (b.Max.Y - b.Min.Y).Ceil()
-
-				if w == 0 || h == 0 {
-					w = 1
-					h = 1
-				}
-			}
-
-			if b.Min.X&((1<<6)-1) != 0 {
-				w++
-			}
-
-			if b.Min.Y&((1<<6)-1) != 0 {
-				h++
-			}
-
-			pixmap := texture.NewPixMap(w, h)
-
-			d := font.Drawer{
-				Dst:  pixmap.NRGBA(),
-				Src:  image.White,
-				Face: fc,
-			}
-
-			x, y := fixed.I((-b.Min.X).Ceil()), fixed.I((-b.Min.Y).Ceil())
-			d.Dot = fixed.Point26_6{X: x, Y: y}
-			d.DrawString(string(i))
-
-			region := fnt.atlas.AddTexture(string(i), pixmap.Width, pixmap.Height, pixmap.Data)
-
-			region.V1, region.V2 = region.V2, region.V1
-
-			pixmap.Dispose()
+			region := rasterizeGlyph(fnt.atlas, fc, i, b, w, h)
 
 			//set w,h and adv, bearing V and bearing H in char
 			advance := float64(gAdv) / 64
@@ -128,6 +93,58 @@ func LoadFont(reader io.Reader) *Font {
 	return fnt
 }
 
+// measureGlyph returns pixel bounds and advance of the glyph at face's size
+func measureGlyph(ttf *sfnt.Font, fc font.Face, buff *sfnt.Buffer, c rune) (b fixed.Rectangle26_6, gAdv fixed.Int26_6, w, h int) {
+	b, gAdv, _ = fc.GlyphBounds(c)
+	w, h = (b.Max.X - b.Min.X).Ceil(), (b.Max.Y - b.Min.Y).Ceil()
+
+	if w == 0 || h == 0 {
+		b, _ = ttf.Bounds(buff, fixed.Int26_6(20), font.HintingFull)
+
+		w, h = (b.Max.X - b.Min.X).Ceil(), (b.Max.Y - b.Min.Y).Ceil()
+
+		if w == 0 || h == 0 {
+			w = 1
+			h = 1
+		}
+	}
+
+	if b.Min.X&((1<<6)-1) != 0 {
+		w++
+	}
+
+	if b.Min.Y&((1<<6)-1) != 0 {
+		h++
+	}
+
+	return
+}
+
+// rasterizeGlyph draws the glyph and puts it in the atlas, needs to be called on GL thread
+func rasterizeGlyph(atlas *texture.TextureAtlas, fc font.Face, c rune, b fixed.Rectangle26_6, w, h int) *texture.TextureRegion {
+	pixmap := texture.NewPixMap(w, h)
+
+	d := font.Drawer{
+		Dst:  pixmap.NRGBA(),
+		Src:  image.White,
+		Face: fc,
+	}
+
+	x, y := fixed.I((-b.Min.X).Ceil()), fixed.I((-b.Min.Y).Ceil())
+	d.Dot = fixed.Point26_6{X: x, Y: y}
+	d.DrawString(string(c))
+
+	region := atlas.AddTexture(string(c), pixmap.Width, pixmap.Height, pixmap.Data)
+
+	if region != nil {
+		region.V1, region.V2 = region.V2, region.V1
+	}
+
+	pixmap.Dispose()
+
+	return region
+}
+
 func LoadTextureFont(path, name string, min, max rune, atlas *texture.TextureAtlas) *Font {
 	font := new(Font)
 
@@ -199,6 +199,21 @@ func (sprite *Sprite) ClearTransformationsOfType(transformationType animation.Tr
 	}
 }
 
+func (sprite *Sprite) RemoveTransformations(transformations []*animation.Transformation) {
+	toRemove := make(map[*animation.Transformation]struct{}, len(transformations))
+	for _, t := range transformations {
+		toRemove[t] = struct{}{}
+	}
+
+	for i := 0; i < len(sprite.transforms); i++ {
+		if _, ok := toRemove[sprite.transforms[i]]; ok {
+			copy(sprite.transforms[i:], sprite.transforms[i+1:])
+			sprite.transforms = sprite.transforms[:len(sprite.transforms)-1]
+			i--
+		}
+	}
+}
+
 func (sprite *Sprite) AdjustTimesToTransformations() {
 	startTime := math.MaxFloat64
 	endTime := -math.MaxFloat64
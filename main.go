@@ -26,11 +26,13 @@ import (
 	difficulty2 "github.com/tsunyoku/danser/app/beatmap/difficulty"
 	"github.com/tsunyoku/danser/app/bmath"
 	camera2 "github.com/tsunyoku/danser/app/bmath/camera"
+	"github.com/tsunyoku/danser/app/dance/benchmark"
 	"github.com/tsunyoku/danser/app/database"
 	"github.com/tsunyoku/danser/app/discord"
 	"github.com/tsunyoku/danser/app/ffmpeg"
 	"github.com/tsunyoku/danser/app/input"
 	"github.com/tsunyoku/danser/app/settings"
+	"github.com/tsunyoku/danser/app/skin/skincheck"
 	"github.com/tsunyoku/danser/app/states"
 	"github.com/tsunyoku/danser/app/utils"
 	"github.com/tsunyoku/danser/build"
@@ -88,8 +90,14 @@ var output string
 var recordMode bool
 var screenshotMode bool
 var screenshotTime float64
+var skinCheckMode bool
 
 func run() {
+	var benchmarkMode bool
+	var benchmarkMaps []*beatmap.BeatMap
+	var benchmarkMods difficulty2.Modifier
+	var benchmarkOutput string
+
 	mainthread.Call(func() {
 		id := flag.Int64("id", -1, "Specify the beatmap id. Overrides other beatmap search flags")
 
@@ -143,11 +151,18 @@ func run() {
 		cs := flag.Float64("cs", math.NaN(), "Modify map's CS, only in cursordance/play modes")
 		hp := flag.Float64("hp", math.NaN(), "Modify map's HP, only in cursordance/play modes")
 
+		seed := flag.Int64("seed", 0, "Replace Dance.Seed setting temporarily, same seed, map and settings always give the same cursor dance, negative picks a random one")
+
+		benchmarkFlag := flag.Bool("benchmark", false, "Run every registered mover on all beatmaps matching beatmap search flags (all beatmaps if none are given) and print quality metrics instead of starting danser")
+		benchmarkOut := flag.String("benchmarkout", "", "Save -benchmark results to a file instead of printing them. Use .json extension for JSON, otherwise a text table is written")
+
+		skinCheck := flag.String("skin-check", "", "Validate given skin instead of starting danser: report the source of every element, broken @2x sizes, animation gaps and missing hitsounds, then save a contact sheet to screenshots folder. Name of the sheet can be set by -out")
+
 		flag.Parse()
 
 		if *out != "" {
 			output = *out
-			if math.IsNaN(*ss) {
+			if math.IsNaN(*ss) && *skinCheck == "" {
 				*record = true
 			}
 		}
@@ -155,6 +170,7 @@ func run() {
 		recordMode = *record
 		screenshotMode = !math.IsNaN(*ss)
 		screenshotTime = *ss
+		skinCheckMode = *skinCheck != ""
 
 		if *record && *play {
 			panic("Incompatible flags selected: -record, -play")
@@ -166,6 +182,10 @@ func run() {
 			panic("Incompatible flags selected: -ss, -play")
 		} else if screenshotMode && recordMode {
 			panic("Incompatible flags selected: -ss, -record")
+		} else if *benchmarkFlag && (*play || *knockout || recordMode || screenshotMode) {
+			panic("Incompatible flags selected: -benchmark, -play/-knockout/-replay/-record/-ss")
+		} else if skinCheckMode && (*play || *knockout || *record || screenshotMode || *benchmarkFlag) {
+			panic("Incompatible flags selected: -skin-check, -play/-knockout/-replay/-record/-ss/-benchmark")
 		}
 
 		modsParsed := difficulty2.ParseMods(*mods)
@@ -198,7 +218,7 @@ func run() {
 
 		closeAfterSettingsLoad := false
 
-		if (*md5+*artist+*title+*difficulty+*creator) == "" && *id < 0 {
+		if (*md5+*artist+*title+*difficulty+*creator) == "" && *id < 0 && !*benchmarkFlag && !skinCheckMode {
 			log.Println("No beatmap specified, closing...")
 			closeAfterSettingsLoad = true
 		}
@@ -213,7 +233,7 @@ func run() {
 		settings.SKIP = *skip
 		settings.START = *start
 		settings.END = *end
-		settings.RECORD = recordMode || screenshotMode
+		settings.RECORD = recordMode || screenshotMode || *benchmarkFlag || skinCheckMode
 
 		if settings.RECORD {
 			bass.Offscreen = true
@@ -229,14 +249,29 @@ func run() {
 		player = nil
 		var beatMap *beatmap.BeatMap = nil
 
-		if !closeAfterSettingsLoad {
+		if !closeAfterSettingsLoad && !skinCheckMode {
 			err := database.Init()
 			if err != nil {
 				log.Println("Failed to initialize database:", err)
 			} else {
 				beatmaps := database.LoadBeatmaps(*noDbCheck)
 
-				if *id > -1 {
+				if *benchmarkFlag {
+					for _, b := range beatmaps {
+						if (*id < 0 || b.ID == *id) &&
+							(*md5 == "" || strings.EqualFold(b.MD5, *md5)) &&
+							(*artist == "" || strings.Contains(strings.ToLower(b.Artist), strings.ToLower(*artist))) &&
+							(*title == "" || strings.Contains(strings.ToLower(b.Name), strings.ToLower(*title))) &&
+							(*difficulty == "" || strings.Contains(strings.ToLower(b.Difficulty), strings.ToLower(*difficulty))) &&
+							(*creator == "" || strings.Contains(strings.ToLower(b.Creator), strings.ToLower(*creator))) {
+							benchmarkMaps = append(benchmarkMaps, b)
+						}
+					}
+
+					if len(benchmarkMaps) > 0 {
+						beatMap = benchmarkMaps[0]
+					}
+				} else if *id > -1 {
 					for _, b := range beatmaps {
 						if b.ID == *id {
 							beatMap = b
@@ -328,6 +363,14 @@ func run() {
 			settings.Skin.CurrentSkin = *skin
 		}
 
+		if skinCheckMode {
+			settings.Skin.CurrentSkin = *skinCheck
+		}
+
+		if *seed != 0 {
+			settings.Dance.Seed = *seed
+		}
+
 		if *quickstart {
 			settings.SKIP = true
 			settings.Playfield.LeadInTime = 0
@@ -375,7 +418,9 @@ func run() {
 			})
 		}
 
-		win.SetTitle("danser " + build.VERSION + " - " + beatMap.Artist + " - " + beatMap.Name + " [" + beatMap.Difficulty + "]")
+		if beatMap != nil {
+			win.SetTitle("danser " + build.VERSION + " - " + beatMap.GetArtist() + " - " + beatMap.GetName() + " [" + beatMap.Difficulty + "]")
+		}
 		input.Win = win
 
 		icon, eee := assets.GetPixmap("assets/textures/dansercoin.png")
@@ -458,6 +503,19 @@ func run() {
 		font.LoadFont(file)
 		file.Close()
 
+		for _, path := range settings.General.FallbackFonts {
+			fFile, err := os.Open(path)
+			if err != nil {
+				continue
+			}
+
+			if err = font.AddFallback(fFile); err != nil {
+				log.Println("Failed to load fallback font", path+":", err)
+			}
+
+			fFile.Close()
+		}
+
 		batch = batch2.NewQuadBatch()
 		batch.Begin()
 		batch.SetColor(1, 1, 1, 1)
@@ -478,6 +536,20 @@ func run() {
 		bass.Init(settings.RECORD)
 		audio.LoadSamples()
 
+		// Benchmark runs outside of the main thread, it calls GL only when needed
+		if *benchmarkFlag {
+			benchmarkMode = true
+			benchmarkMods = modsParsed
+			benchmarkOutput = *benchmarkOut
+
+			return
+		}
+
+		// Textures are uploaded on the main thread so the check has to run after this call
+		if skinCheckMode {
+			return
+		}
+
 		speedBefore := settings.SPEED
 
 		if modsParsed.Active(difficulty2.Nightcore) {
@@ -521,24 +593,26 @@ func run() {
 		limiter = frame.NewLimiter(int(settings.Graphics.FPSCap))
 	})
 
-	if recordMode {
+	if benchmarkMode {
+		benchmark.Run(benchmarkMaps, benchmarkMods, benchmarkOutput)
+	} else if skinCheckMode {
+		skincheck.Run(output)
+	} else if recordMode {
 		mainLoopRecord()
 	} else if screenshotMode {
 		mainLoopSS()
 	} else {
 		mainLoopNormal()
 	}
+
+	if player != nil {
+		player.Dispose()
+	}
 }
 
 func mainLoopRecord() {
 	count := 0
 
-	fps := float64(settings.Recording.FPS)
-
-	if settings.Recording.MotionBlur.Enabled {
-		fps *= float64(settings.Recording.MotionBlur.OversampleMultiplier)
-	}
-
 	w, h := int(settings.Graphics.GetWidth()), int(settings.Graphics.GetHeight())
 
 	var fbo *buffer.Framebuffer
@@ -547,23 +621,37 @@ func mainLoopRecord() {
 		fbo = buffer.NewFrameMultisampleScreen(w, h, false, 0)
 	})
 
-	ffmpeg.StartFFmpeg(int(fps), w, h)
+	ffmpeg.StartFFmpeg(settings.Recording.FPS, w, h)
 
-	updateFPS := math.Max(fps, 1000)
-	updateDelta := 1000 / updateFPS
-	fpsDelta := 1000 / fps
-
-	deltaSumF := fpsDelta
+	updateDelta := 1000 / math.Max(float64(ffmpeg.GetRenderFPS()*bmath.MaxI(1, settings.Recording.MotionBlur.OversampleMultiplier)), 1000)
 
 	p, _ := player.(*states.Player)
 
+	sharpHUD := settings.Recording.MotionBlur.Enabled && settings.Recording.MotionBlur.SharpHUD
+	p.SetSeparateHUD(sharpHUD)
+
 	//maxFrames := int(p.RunningTime / settings.SPEED / 1000 * fps)
 
 	var lastProgress, progress int
 
-	for !p.Update(updateDelta) {
-		deltaSumF += updateDelta
-		if deltaSumF >= fpsDelta {
+	elapsed := 0.0
+	finished := false
+
+	for !finished {
+		samples := ffmpeg.NextFrame(p.GetCursorSpeed())
+
+		for _, sampleTime := range samples {
+			for elapsed < sampleTime && !finished {
+				delta := math.Min(updateDelta, sampleTime-elapsed)
+
+				finished = p.Update(delta)
+				elapsed += delta
+			}
+
+			if finished {
+				break
+			}
+
 			mainthread.Call(func() {
 				fbo.Bind()
 
@@ -573,26 +661,30 @@ func mainLoopRecord() {
 				pushFrame()
 				viewport.Pop()
 
-				ffmpeg.MakeFrame()
+				if ffmpeg.EndSubFrame() {
+					if sharpHUD {
+						pushHUD(p)
+					}
 
-				fbo.Unbind()
+					ffmpeg.MakeFrame()
 
-				count++
+					count++
 
-				progress = int(math.Round(p.GetTimeOffset() / p.RunningTime /*float64(count) / float64(maxFrames)*/ * 100))
+					progress = int(math.Round(p.GetTimeOffset() / p.RunningTime /*float64(count) / float64(maxFrames)*/ * 100))
 
-				if progress%5 == 0 && lastProgress != progress {
-					fmt.Println()
-					log.Println(fmt.Sprintf("Progress: %d%%", progress))
-					lastProgress = progress
+					if progress%5 == 0 && lastProgress != progress {
+						fmt.Println()
+						log.Println(fmt.Sprintf("Progress: %d%%", progress))
+						lastProgress = progress
+					}
 				}
+
+				fbo.Unbind()
 			})
 
 			mainthread.Call(func() {
 				ffmpeg.CheckData()
 			})
-
-			deltaSumF -= fpsDelta
 		}
 	}
 
@@ -600,9 +692,9 @@ func mainLoopRecord() {
 		ffmpeg.StopFFmpeg()
 	})
 
-	bass.SaveToFile(filepath.Join(settings.Recording.OutputDir, ffmpeg.GetFileName()+".wav"))
+	stems := bass.SaveToFile(filepath.Join(settings.Recording.OutputDir, ffmpeg.GetFileName()+".wav"))
 
-	ffmpeg.Combine(output)
+	ffmpeg.Combine(output, stems)
 }
 
 func mainLoopSS() {
@@ -760,6 +852,19 @@ func pushFrame() {
 	viewport.Pop()
 }
 
+// pushHUD draws only the HUD on top of already blended frame
+func pushHUD(p *states.Player) {
+	blend.Enable()
+	blend.SetFunction(blend.One, blend.OneMinusSrcAlpha)
+
+	viewport.Push(int(settings.Graphics.GetWidth()), int(settings.Graphics.GetHeight()))
+
+	p.DrawHUD()
+
+	blend.ClearStack()
+	viewport.Pop()
+}
+
 func setWorkingDirectory() {
 	exec, err := os.Executable()
 	if err != nil {
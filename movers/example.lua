@@ -0,0 +1,46 @@
+-- Example scripted mover, use it by adding "example" to Dance.Movers or Dance.Spinners in settings.
+-- Objects are indexed from 1 and expose: id, type ("circle", "slider", "spinner"), start_time, end_time, duration,
+-- start_x, start_y, end_x, end_y (stacked), new_combo, is_long, start_angle, end_angle, part_len, position_at(time)
+
+local from_x, from_y, to_x, to_y = 0, 0, 0, 0
+local start_time, end_time = 0, 0
+
+function reset(mods)
+end
+
+-- Called with objects that are left, returns how many of them were consumed (at least 2)
+function set_objects(objs)
+    local from, to = objs[1], objs[2]
+
+    from_x, from_y = from.end_x, from.end_y
+    to_x, to_y = to.start_x, to.start_y
+
+    start_time = from.end_time
+    end_time = to.start_time
+
+    return 2
+end
+
+function update(time)
+    local t = math.max(0, math.min(1, (time - start_time) / math.max(1, end_time - start_time)))
+    t = t * t * (3 - 2 * t)
+
+    return from_x + (to_x - from_x) * t, from_y + (to_y - from_y) * t
+end
+
+function get_end_time()
+    return end_time
+end
+
+local spin_start = 0
+
+function spinner_init(start, finish)
+    spin_start = start
+end
+
+function spinner_position_at(time)
+    local angle = (time - spin_start) / 1000 * 2 * math.pi * 3
+    local r = danser.spinner_radius * (0.75 + 0.25 * math.sin(angle * 5))
+
+    return 256 + math.cos(angle) * r, 192 + math.sin(angle) * r
+end